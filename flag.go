@@ -9,15 +9,22 @@ import (
 	"fmt"
 	"github.com/kohirens/stdlib/log"
 	"github.com/kohirens/tmplpress/internal/msg"
+	"github.com/kohirens/tmplpress/internal/press"
+	"strings"
 )
 
 type appFlags struct {
 	AnswersPath    string // The path to a file containing values to variables to be parsed.
 	Branch         string // The desired branch of the template to.
 	CommitHash     string // Git commit hash of the current version.
+	Conflicts      rules  // Policies for the files an existing output directory already has.
 	CurrentVersion string // Current semantic version of the application.
+	DataFiles      paths  // JSON or YAML files given to the template as .Data.
 	DefaultVal     string // A default placeholder value when a placeholder is empty.
+	FixedTime      string // A time, in RFC 3339 format, to press the template at instead of now.
+	FixedUser      string // A user, such as "Ann <ann@example.com>", to press the template as instead of the one in the git config.
 	Help           bool   // The usage for all flags.
+	Jobs           int    // The number of files to press at the same time.
 	TmplPath       string // The URL or local template path to a template.
 	TmplType       string // Indicate the type of package for a template, such as a local directory or git repository.
 	OutPath        string // The location to save the processed template output.
@@ -31,9 +38,14 @@ func defineFlags(af *appFlags) {
 	// Note: These are defined in alphabetical order.
 	flag.StringVar(&af.AnswersPath, "answer-path", "", um["answer-path"]) // TODO: BREAKING Change to "answers"
 	flag.StringVar(&af.Branch, "branch", "main", um["branch"])            // TODO: BREAKING Change git-ref, since refs alreay point to a complete SHA-1
+	flag.Var(&af.DataFiles, "data", um["data"])
 	flag.StringVar(&af.DefaultVal, "default-val", " ", um["default-val"])
+	flag.StringVar(&af.FixedTime, "fixed-time", "", um["fixed-time"])
+	flag.StringVar(&af.FixedUser, "fixed-user", "", um["fixed-user"])
 	flag.BoolVar(&af.Help, "help", false, um["help"])
 	flag.BoolVar(&af.Help, "h", false, um["help"]+" (shorthand)")
+	flag.IntVar(&af.Jobs, "jobs", 0, um["jobs"])
+	flag.Var(&af.Conflicts, "on-conflict", um["on-conflict"])
 	flag.StringVar(&af.OutPath, "out-path", "", um["out-path"])       // TODO: BREAKING remove this will be a required 2nd argument.
 	flag.StringVar(&af.TmplPath, "tmpl-path", "", um["tmpl-path"])    // TODO: BREAKING remove this will be a required 1st argument.
 	flag.StringVar(&af.TmplType, "tmpl-type", "git", um["tmpl-type"]) // TODO: BREAKING Remove, we only use git now.
@@ -78,3 +90,38 @@ func parseFlags(af *appFlags) error {
 
 	return nil
 }
+
+// paths Paths of files, from a flag that can be given more than once.
+type paths []string
+
+func (l *paths) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *paths) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// rules Conflict policies, from a flag that can be given more than once.
+type rules []*press.ConflictRule
+
+func (l *rules) String() string {
+	values := make([]string, len(*l))
+	for i, r := range *l {
+		values[i] = r.String()
+	}
+
+	return strings.Join(values, ",")
+}
+
+func (l *rules) Set(value string) error {
+	r, e := press.ParseConflictRule(value)
+	if e != nil {
+		return e
+	}
+
+	*l = append(*l, r)
+
+	return nil
+}
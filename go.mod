@@ -6,7 +6,7 @@ toolchain go1.21.6
 
 require (
 	github.com/kohirens/stdlib v0.0.0-20240317173523-467fce39bae3
-	github.com/ryanuber/go-glob v1.0.0
 	golang.org/x/mod v0.16.0
 	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -9,6 +9,9 @@ var Stderr = struct {
 	CannotReadFile         string
 	CannotReadAnswerFile   string
 	CannotRemoveDir        string
+	CannotWriteReport      string
+	CommitStaging          string
+	ConflictPolicy         string
 	CouldNot               string
 	CouldNotCloseFile      string
 	CouldNotDecode         string
@@ -16,48 +19,128 @@ var Stderr = struct {
 	CouldNotMakeCacheDir   string
 	CouldNotSaveConf       string
 	CouldNotWriteFile      string
+	DataFileType           string
+	DecodeDataFile         string
+	DelimitersGlobal       string
 	EmptyDirFilename       string
 	EmptyPlaceholderName   string
 	EmptyRegExp            string
 	FatalHeader            string
 	Filename               string
 	FileTooBig             string
+	FixedTime              string
+	FixedUser              string
 	FlagOrderErr           string
+	ForEachItemName        string
+	ForEachItemReserved    string
+	ForEachList            string
+	FrontMatterDecode      string
+	FrontMatterDelims      string
+	FrontMatterFile        string
+	FrontMatterIf          string
+	FrontMatterMode        string
+	FrontMatterNoEnd       string
+	FrontMatterPath        string
 	GettingAnswers         string
 	GitFetchFailed         string
 	GetLatestTag           string
 	GetRemoteTags          string
+	Interrupted            string
 	InvalidCmd             string
 	InvalidManifest        string
+	InvalidManifestVersion string
+	InvalidMinPressVersion string
 	InvalidNoArgs          string
 	InvalidNoSubCmdArgs    string
 	InvalidPlaceholderName string
 	InvalidRegExp          string
 	InvalidTmplDir         string
+	LinkBroken             string
+	LinkOutsideOutput      string
+	LinkOutsideTemplate    string
+	LinkToDir              string
+	ListItems              string
+	ManifestDecode         string
+	ManifestTooNew         string
 	ManifestValidation     string
+	MigrateConflict        string
+	MigrateManifest        string
 	MissingTmplJson        string
 	MissingTmplJsonVersion string
+	Mode                   string
 	NewManifest            string
 	NoDir                  string
 	NoGitTagFound          string
 	NoInput                string
+	NoPartialsDir          string
 	NoPath                 string
 	NoPlaceholder          string
 	NoSetting              string
+	OutPathOutside         string
+	OutputCollision        string
+	OutputExists           string
+	OutputTypeCollision    string
 	ParseBool              string
 	ParseGenerateInput     string
 	ParseInt               string
+	ParsePartial           string
 	ParseUInt              string
 	ParseValidateInput     string
 	ParsingConfigArgs      string
 	PathNotAllowed         string
+	PathOutsideTemplate    string
+	PatternChanged         string
 	PlaceholdersProperty   string
+	PressFailed            string
+	PressFailedReport      string
+	PressVersionTooOld     string
+	RemoveStaging          string
+	RenderPath             string
+	ReservedName           string
 	RunGitFailed           string
+	SchemaBadRef           string
+	SchemaCheck            string
+	SchemaConst            string
+	SchemaDecode           string
+	SchemaDependent        string
+	SchemaDocDecode        string
+	SchemaEnum             string
+	SchemaExclusiveMaximum string
+	SchemaExclusiveMinimum string
+	SchemaKeyword          string
+	SchemaMaximum          string
+	SchemaMaxItems         string
+	SchemaMaxLength        string
+	SchemaMinimum          string
+	SchemaMinItems         string
+	SchemaMinLength        string
+	SchemaMultipleOf       string
+	SchemaNot              string
+	SchemaNotLoaded        string
+	SchemaPattern          string
+	SchemaRequired         string
+	SchemaType             string
+	SchemaUniqueItems      string
+	SchemaUnknownProperty  string
+	SchemaViolation        string
+	SchemaViolations       string
+	Staging                string
+	TextBOM                string
+	TextDecode             string
+	TextEncode             string
+	TextEncoding           string
+	TextGlobal             string
+	TextLineEndings        string
+	TextNoBOM              string
 	TmplManifest404        string
 	TmplOutput             string
+	TypedValue             string
+	UndoCommit             string
 	UnhandledHttpErr       string
 	ParsingFile            string
 	PathNotExist           string
+	UnknownPressVersion    string
+	YamlDocuments          string
 }{
 	AnswerFile404:          "could not find the answer file, please specify a path to a valid answer file that exist: given %q",
 	AppDataDir:             "the following error occurred trying to get the app data directory: %q",
@@ -67,6 +150,9 @@ var Stderr = struct {
 	CannotReadAnswerFile:   "there was an error reading the answer file %q: %s",
 	CannotReadFile:         "could not read file %v: %v",
 	CannotRemoveDir:        "could not remove dir %v: %v",
+	CannotWriteReport:      "could not write the failure report %v: %v",
+	CommitStaging:          "could not move the pressed files from %v to %v: %v",
+	ConflictPolicy:         "invalid conflict policy %q, must be a policy or a pattern=policy pair, where the policy is one of backup, fail, overwrite, prompt, or skip",
 	CouldNot:               "could not %s",
 	CouldNotCloseFile:      "could not close file %v, %v",
 	CouldNotDecode:         "could not decode %q, error: %s",
@@ -74,43 +160,123 @@ var Stderr = struct {
 	CouldNotMakeCacheDir:   "could not make cache directory, error: %s",
 	CouldNotSaveConf:       "could not save a config file, reason: %v",
 	CouldNotWriteFile:      "could not write file %v, reason: %v",
+	DataFileType:           "data file %v must be JSON or YAML, ending in .json, .yaml, or .yml",
+	DecodeDataFile:         "could not decode data file %v: %v",
+	DelimitersGlobal:       "there are %d delimiters for all files, only one can have no \"files\"",
 	EmptyDirFilename:       "bad filename %q was set for property emptyDirFile",
 	EmptyPlaceholderName:   "empty placeholder %q, %q",
 	EmptyRegExp:            "regular expression validation rule was left empty, see rule:  %v ",
 	FatalHeader:            "\nfatal error detected: ",
 	Filename:               "invalid filename/pattern %q",
-	FileTooBig:             "template file too big to parse, must be less thatn %v bytes",
+	FileTooBig:             "%v is too big to render, %v bytes, the limit is %v bytes; list it in copyAsIs, or raise maxRenderSize",
+	FixedTime:              "could not parse -fixed-time %q, it should be like 2024-01-02T15:04:05Z: %v",
+	FixedUser:              "could not parse -fixed-user %q, it should be like \"Ann <ann@example.com>\": %v",
 	FlagOrderErr:           "flag %v MUST come before any non-flag arguments, a fix would be to move this flag to the left of other input arguments",
+	ForEachItemName:        "the for-each item name %q is also a placeholder, use \"as\" to give the items another name",
+	ForEachItemReserved:    "the for-each item name %q is reserved, use \"as\" to give the items another name",
+	ForEachList:            "for-each list %q of %v: %v",
+	FrontMatterDecode:      "could not decode the front matter: %v",
+	FrontMatterDelims:      "the front matter delimiters need a left and right",
+	FrontMatterFile:        "front matter of %v: %v",
+	FrontMatterIf:          "could not fill in the placeholders in the condition %q: %v",
+	FrontMatterMode:        "the front matter mode %q is not an octal file mode, such as \"0755\"",
+	FrontMatterNoEnd:       "the front matter has no %q line to end it",
+	FrontMatterPath:        "the front matter path %q has to be in the output directory",
 	GettingAnswers:         "problem getting answers; error %q",
 	GetLatestTag:           "failed to get latest tag from %v: %v",
+	Interrupted:            "pressing was interrupted",
 	InvalidCmd:             "invalid command %v",
 	InvalidManifest:        "invalid manifest found at %v, will replace it with the default",
+	InvalidManifestVersion: "invalid version %q in the template manifest, expected a semantic version such as %q",
+	InvalidMinPressVersion: "invalid minPressVersion %q in the template manifest, expected a semantic version",
 	InvalidNoArgs:          "invalid number of arguments passed to the config command, please see config -help for usage",
 	InvalidNoSubCmdArgs:    "subcommand %v takes at least %v arguments, run \"%[1]s -h\" for usage details",
 	InvalidPlaceholderName: "invalid placeholder name %v",
 	InvalidRegExp:          "invalid regular expression %q, %v",
 	InvalidTmplDir:         "invalid template directory %q",
+	LinkBroken:             "could not read the link %v: %v",
+	LinkOutsideOutput:      "the link %v cannot point outside of the output directory, to %v",
+	LinkOutsideTemplate:    "the link %v cannot be followed outside of the template, to %v",
+	LinkToDir:              "the link %v is to a directory, which cannot be followed, set symlinks to \"preserve\"",
+	ListItems:              "could not read the items of the list, it should be a JSON array or comma separated: %v",
+	ManifestDecode:         "could not decode the manifest %v, %v",
+	ManifestTooNew:         "template manifest version %v is newer than %v, the latest this version of tmplpress supports; please upgrade with: %v",
 	ManifestValidation:     "problem with manifest %v, %v",
+	MigrateConflict:        "cannot rename %q to %q, the manifest already has both",
+	MigrateManifest:        "could not migrate the manifest to version %v, %v",
 	MissingTmplJson:        "%s is a file that is required to be in the template, there was a problem reading %q; error %q",
 	MissingTmplJsonVersion: "missing the Version property in template.json",
+	Mode:                   "the mode %q of modes is not an octal file mode, such as \"0755\"",
 	NewManifest:            "could not initialize a new manifest, %v",
 	NoDir:                  "directory %v was not found",
 	NoGitTagFound:          "no tag found in %v",
 	NoInput:                "no input",
+	NoPartialsDir:          "the partials directory %v does not exist",
 	NoPath:                 "unable to determine absolute path for %v, because %v",
 	NoPlaceholder:          "there is no placeholder %v",
 	NoSetting:              "no setting named %q found",
+	OutPathOutside:         "the template file %v would be output outside of the output directory, to %q",
+	OutputCollision:        "%v and %v would both be output to %v",
+	OutputExists:           "%v already has these files, and their conflict policy is to fail: %v",
+	OutputTypeCollision:    "%v already has a file where a directory is pressed, or a directory where a file is, no conflict policy can replace them: %v",
 	ParseBool:              "%v is not a valid boolean value",
 	ParseGenerateInput:     "could not parse generate input: %v",
 	ParseInt:               "could not parse %v as a integer, %v",
+	ParsePartial:           "could not parse the partial %v: %v",
 	ParseUInt:              "could not parse %v as a natural number, %v",
 	ParseValidateInput:     "could not parse validate input: %v",
 	ParsingConfigArgs:      "error parsing config command args: %v",
 	PathNotAllowed:         "path/URL to template is not in the allow-list",
+	PathOutsideTemplate:    "the %v path %v in the manifest is outside of the template",
+	PatternChanged:         "the pattern %v may match other files than before, patterns now work as in a .gitignore file, see https://git-scm.com/docs/gitignore#_pattern_format",
 	PlaceholdersProperty:   "bad placeholders variables %v, %v",
+	PressFailed:            "pressing %v failed, nothing was output: %v",
+	PressFailedReport:      "pressing %v failed, nothing was output: %v; see %v",
+	PressVersionTooOld:     "this template requires tmplpress %v or newer, but this is version %v; please upgrade with: %v",
+	RemoveStaging:          "could not remove the staging directory %v: %v",
+	RenderPath:             "could not fill in the placeholders in the path %v: %v",
+	ReservedName:           "placeholder %q is reserved, give it another name",
+	SchemaBadRef:           "could not resolve schema reference %q",
+	SchemaCheck:            "could not check %v against the schema, %v",
+	SchemaConst:            "must be %v",
+	SchemaDecode:           "could not decode JSON schema, %v",
+	SchemaDependent:        "property %q is required when %q is set",
+	SchemaDocDecode:        "could not decode JSON document, %v",
+	SchemaEnum:             "%v is not one of %v",
+	SchemaExclusiveMaximum: "%v must be less than %v",
+	SchemaExclusiveMinimum: "%v must be greater than %v",
+	SchemaKeyword:          "unsupported schema keyword %q at %v",
+	SchemaMaximum:          "%v must be at most %v",
+	SchemaMaxItems:         "must have at most %v items",
+	SchemaMaxLength:        "must be at most %v characters",
+	SchemaMinimum:          "%v must be at least %v",
+	SchemaMinItems:         "must have at least %v items",
+	SchemaMinLength:        "must be at least %v characters",
+	SchemaMultipleOf:       "%v must be a multiple of %v",
+	SchemaNot:              "must not match the schema",
+	SchemaNotLoaded:        "no JSON schema loaded to validate against",
+	SchemaPattern:          "%q does not match pattern %q",
+	SchemaRequired:         "missing required property %q",
+	SchemaType:             "expected type %v, got %v",
+	SchemaUniqueItems:      "duplicate of item %v",
+	SchemaUnknownProperty:  "unknown property %q",
+	SchemaViolation:        "%v: %v",
+	SchemaViolations:       "%v does not conform to the schema:\n%v",
+	Staging:                "could not make a staging directory for %v: %v",
+	TextBOM:                "unknown byte order mark policy %q in text, use add, preserve, or remove",
+	TextDecode:             "could not decode %v: %v",
+	TextEncode:             "could not encode the output of %v: %v",
+	TextEncoding:           "unknown encoding %q of text, such as \"utf-16le\" or \"windows-1252\"",
+	TextGlobal:             "there are %d text formats for all files, only one can have no \"files\"",
+	TextLineEndings:        "unknown line endings %q of text, must be \"lf\", \"crlf\", or \"preserve\"",
+	TextNoBOM:              "cannot add a byte order mark to text output in %v, it has none",
 	TmplManifest404:        "the required manifest %q file was not found",
 	TmplOutput:             "template has NOT been cloned locally",
+	TypedValue:             "the placeholder %v is typed %v in the manifest, but its value %q is not one: %v",
+	UndoCommit:             "could not move %v back to %v: %v",
 	UnhandledHttpErr:       "template Download aborted; I'm coded to NOT do anything when HTTP status is %q and status code is %d",
 	ParsingFile:            "could not parse file %v, error: %v",
 	PathNotExist:           "could not locate the path %v",
+	UnknownPressVersion:    "the template requires tmplpress %v or newer, but the version of this build is unknown, so it cannot be checked",
+	YamlDocuments:          "only one document is supported",
 }
@@ -7,14 +7,26 @@ var Stdout = struct {
 	AppCacheDir           string
 	AppDataDir            string
 	Assignment            string
+	Binary                string
 	CloningToCache        string
 	ConfigMethodSetting   string
+	Conflict              string
+	ConflictBackup        string
+	ConflictPrompt        string
+	ConflictSkip          string
 	CopyAsIs              string
 	CurrentVersion        string
 	CurrentVersionInfo    string
 	Cwd                   string
+	DataReplaced          string
+	FailureReport         string
+	FrontMatterExclude    string
+	FrontMatterRaw        string
 	GeneratedManifest     string
+	LegacyManifest        string
+	Link                  string
 	MadeNewConfig         string
+	MigratingManifest     string
 	NoPlaceholders        string
 	NumNonFlagArgs        string
 	NumParsedFlags        string
@@ -28,11 +40,14 @@ var Stdout = struct {
 	ReadConfig            string
 	RelativeDir           string
 	RepoDir               string
+	ReplaceFilesIgnored   string
 	RepoInfo              string
 	SaveData              string
 	SaveDir               string
+	SchemaVersionSkip     string
 	SetValue              string
 	Skipping              string
+	Staging               string
 	TemplatePath          string
 	TemplatePlaceholders  string
 	TemplateVersion       string
@@ -48,14 +63,26 @@ var Stdout = struct {
 	AppCacheDir:           "app cache dir = %v",
 	AppDataDir:            "app data dir is %v",
 	Assignment:            "%v = %q",
+	Binary:                "file %v looks binary, it will be copied as-is",
 	CloningToCache:        "no cache; cloning %v to %v",
+	Conflict:              "%v already exists, policy: %v",
+	ConflictBackup:        "moving the existing %v to %v",
+	ConflictPrompt:        "\n%v already exists, overwrite, skip, or backup? [o/S/b]: ",
+	ConflictSkip:          "keeping the existing %v",
 	CopyAsIs:              "file %v will be copied as-is",
 	ConfigMethodSetting:   "config.%v(%v)",
 	CurrentVersion:        "%v, %v",
 	CurrentVersionInfo:    "version: %v, %v",
 	Cwd:                   "current working directory is %v",
+	DataReplaced:          "data %v replaced by %v",
+	FailureReport:         "pressing into %v failed\nfile: %v\nerror: %v\n",
+	FrontMatterExclude:    "skipping %v, its front matter condition is false",
+	FrontMatterRaw:        "output %v raw, as its front matter says",
 	GeneratedManifest:     "manifest generated %v",
+	LegacyManifest:        "template manifest version %v predates %v, reading it with a compatibility decoder",
+	Link:                  "link %v to %v will be made",
 	MadeNewConfig:         "saved %d bytes to a new config %v",
+	MigratingManifest:     "migrating manifest from version %v to %v",
 	NoPlaceholders:        "this template contains no placeholders/actions, which is ok",
 	NumNonFlagArgs:        "number of non-flag arguments passed in: %d",
 	NumParsedFlags:        "number of parsed flags = %v",
@@ -69,11 +96,14 @@ var Stdout = struct {
 	ReadConfig:            "reading config file %v",
 	RelativeDir:           "relativePath dir: %v",
 	RepoDir:               "repoDir = %q",
+	ReplaceFilesIgnored:   "the replace files %v of a legacy manifest are not supported, the whole directory is substituted",
 	RepoInfo:              "repo = %q; %q",
 	SaveData:              "save data: %s",
 	SaveDir:               "save dir: %v",
+	SchemaVersionSkip:     "not validating %v against the schema, its version %q is not a %v.x version",
 	SetValue:              "%v value = %v",
 	Skipping:              "skipping: %v",
+	Staging:               "pressing into the staging directory %v",
 	TemplatePath:          "template manifest path: %v",
 	TemplatePlaceholders:  "TmplJson.Placeholders = %v",
 	TemplateVersion:       "TmplJson.Version = %v",
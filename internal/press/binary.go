@@ -0,0 +1,87 @@
+package press
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffLen Number of bytes at the start of a file looked at to decide it is
+// binary, the same as Git.
+const sniffLen = 8000
+
+// IsBinary Indicates content looks binary, so it cannot be a template. That
+// is when the start of it has a NUL byte, is not valid UTF-8, or is of a type
+// that is not text, such as an image, font, or archive. Content that starts
+// with a byte order mark is text, such as UTF-16, which has NUL bytes.
+func IsBinary(content []byte) bool {
+	for _, b := range boms {
+		if bytes.HasPrefix(content, b.bom) {
+			return false
+		}
+	}
+
+	head := content
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+		// Do not count a character cut in half at the end as invalid.
+		for i := 1; i < utf8.UTFMax && i <= len(head); i++ {
+			if utf8.RuneStart(head[len(head)-i]) {
+				if !utf8.FullRune(head[len(head)-i:]) {
+					head = head[:len(head)-i]
+				}
+				break
+			}
+		}
+	}
+
+	if bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(head) {
+		return true
+	}
+
+	return !isTextType(http.DetectContentType(head))
+}
+
+// ChecksBinary Indicates a file is copied as-is when its content looks
+// binary, which is unless it is listed in render, or has an encoding in text.
+func (tm *TmplManifest) ChecksBinary(relativePath string) bool {
+	if InSkipArray(relativePath, tm.Render) {
+		return false
+	}
+
+	tf := tm.TextFor(relativePath)
+
+	return tf == nil || tf.Encoding == ""
+}
+
+// SniffBinary Indicates the file looks binary, reading no more of it than
+// needed to tell, see IsBinary.
+func SniffBinary(filename string) (bool, error) {
+	f, e1 := os.Open(filename)
+	if e1 != nil {
+		return false, fmt.Errorf(msg.Stderr.CannotReadFile, filename, e1.Error())
+	}
+	defer f.Close()
+
+	// Read a little more, so a character cut at the end can be told apart
+	// from an invalid one.
+	head := make([]byte, sniffLen+utf8.UTFMax)
+	n, e2 := io.ReadFull(f, head)
+	if e2 != nil && e2 != io.EOF && e2 != io.ErrUnexpectedEOF {
+		return false, fmt.Errorf(msg.Stderr.CannotReadFile, filename, e2.Error())
+	}
+
+	return IsBinary(head[:n]), nil
+}
+
+// isTextType Indicates a content type, from http.DetectContentType, is that
+// of text. PostScript is recognized by its text header.
+func isTextType(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/") ||
+		strings.HasPrefix(contentType, "application/postscript")
+}
@@ -0,0 +1,77 @@
+package press
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    bool
+	}{
+		{"empty", []byte{}, false},
+		{"text", []byte("# {{.appName}}\n"), false},
+		{"utf-8", []byte("café {{.appName}}\n"), false},
+		{"utf-16-bom", []byte("\xff\xfea\x00\n\x00"), false},
+		{"html", []byte("<!DOCTYPE html><title>{{.appName}}</title>"), false},
+		{"nul", []byte("a\x00b"), true},
+		{"latin-1", []byte("caf\xe9\n"), true},
+		{"png", []byte("\x89PNG\r\n\x1a\n"), true},
+		{"gif", []byte("GIF89a"), true},
+		{"pdf", []byte("%PDF-1.7\n"), true},
+		{"zip", []byte("PK\x03\x04"), true},
+		{"woff2", []byte("wOF2"), true},
+		{"nul-after-sniff", append(bytes.Repeat([]byte("a"), sniffLen), 0), false},
+		{"rune-cut-at-sniff", []byte(strings.Repeat("a", sniffLen-1) + "é"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBinary(tt.content); got != tt.want {
+				t.Errorf("IsBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintBinary(t *testing.T) {
+	fixture := fixtureDir + PS + "binary-01"
+	outDir := tmpDir + PS + "binary-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	if e := Print(fixture, outDir, map[string]string{"appName": "tmplpress"}, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"sniffed-as-binary", "app.wasm", "\x00asm\x01\x00\x00\x00{{.appName}}"},
+		{"nul-in-header", "icon.ico", "\x00\x00\x01\x00\x01\x00\x10\x10{{.appName}}"},
+		{"render", "legacy.ini", "name=caf\xe9 tmplpress\n"},
+		{"text", "README.md", "# tmplpress\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := os.ReadFile(outDir + PS + tt.file)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,24 +16,27 @@ const (
 
 // AppData runtime settings shared throughout the application.
 type AppData struct {
-	AnswersJson  *AnswersJson // data use for template processing
-	CacheDir     string       // Directory to store app data.
-	DataDir      string       // Directory to store app data.
-	Path         string       // Path to configuration file.
-	SubCmd       string       // sub-command to execute
-	Tmpl         string       // Path to template, this will be the cached path.
-	TmplLocation string       // Indicates local or remote location to downloaded
+	AnswersJson   *AnswersJson // data use for template processing
+	CacheDir      string       // Directory to store app data.
+	DataDir       string       // Directory to store app data.
+	MaxRenderSize int64        // Largest file, in bytes, to render.
+	Path          string       // Path to configuration file.
+	SubCmd        string       // sub-command to execute
+	Tmpl          string       // Path to template, this will be the cached path.
+	TmplLocation  string       // Indicates local or remote location to downloaded
 	//	TmplJson              *TmplJson // Data about the template such as placeholders, their descriptions, version, etc.
 }
 
 type ConfigSaveData struct {
-	CacheDir string
+	CacheDir      string
+	MaxRenderSize int64 // The largest file, in bytes, that is rendered.
 }
 
 func NewAppData(sc *ConfigSaveData) (*AppData, error) {
 	// Override defaults with user settings.
 	ad := &AppData{
-		CacheDir: sc.CacheDir,
+		CacheDir:      sc.CacheDir,
+		MaxRenderSize: sc.MaxRenderSize,
 	}
 
 	return ad, nil
@@ -0,0 +1,182 @@
+package press
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/kohirens/stdlib/fsio"
+	"github.com/kohirens/stdlib/log"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Policies for a file the output directory already has.
+const (
+	// ConflictBackup Rename the existing file with the backup suffix, with a
+	// number after it when taken, then output the file.
+	ConflictBackup = "backup"
+	// ConflictFail Output nothing.
+	ConflictFail = "fail"
+	// ConflictOverwrite Replace the existing file.
+	ConflictOverwrite = "overwrite"
+	// ConflictPrompt Ask which of the other policies to use for the file.
+	ConflictPrompt = "prompt"
+	// ConflictSkip Keep the existing file.
+	ConflictSkip = "skip"
+
+	backupSuffix = ".orig"
+)
+
+// ConflictRule The policy for a file the output directory already has, when
+// its output path matches the pattern, or for any file when there is none.
+type ConflictRule struct {
+	Files  string
+	Policy string
+}
+
+func (r *ConflictRule) String() string {
+	if r.Files == "" {
+		return r.Policy
+	}
+
+	return r.Files + "=" + r.Policy
+}
+
+// ParseConflictRule Read a rule that is a policy, or a pattern=policy pair.
+func ParseConflictRule(value string) (*ConflictRule, error) {
+	r := &ConflictRule{Policy: value}
+
+	if i := strings.LastIndex(value, "="); i >= 0 {
+		r.Files, r.Policy = value[:i], value[i+1:]
+	}
+
+	switch r.Policy {
+	case ConflictBackup, ConflictFail, ConflictOverwrite, ConflictPrompt, ConflictSkip:
+		return r, nil
+	}
+
+	return nil, fmt.Errorf(msg.Stderr.ConflictPolicy, value)
+}
+
+// conflictPolicy The policy for a file, the first rule with a pattern that
+// matches it wins, then the last rule without a pattern. Files are overwritten
+// when no rule applies.
+func conflictPolicy(rules []*ConflictRule, outPath string) string {
+	policy := ConflictOverwrite
+
+	for _, r := range rules {
+		if r.Files == "" {
+			policy = r.Policy
+		}
+	}
+
+	for _, r := range rules {
+		if r.Files != "" && InSkipArray(outPath, []string{r.Files}) {
+			return r.Policy
+		}
+	}
+
+	return policy
+}
+
+// resolveConflicts The policy for each file pressed to the staging directory
+// that the output directory already has, keyed by its output path. The user
+// is asked for the policy of the files with the prompt policy. Returns an
+// error, naming the files, when any has the fail policy.
+func resolveConflicts(staging, outDir string, rules []*ConflictRule, answers *bufio.Scanner) (map[string]string, error) {
+	policies := make(map[string]string)
+
+	if !fsio.Exist(outDir) {
+		return policies, nil
+	}
+
+	var conflicts, collisions []string
+
+	e1 := filepath.Walk(staging, func(sourcePath string, fi os.FileInfo, wErr error) error {
+		if wErr != nil {
+			return wErr
+		}
+
+		outPath := filepath.ToSlash(strings.TrimLeft(strings.TrimPrefix(sourcePath, staging), "\\/"))
+		if outPath == "" {
+			return nil
+		}
+
+		if _, e := os.Lstat(outDir + PS + outPath); e != nil {
+			return nil
+		}
+
+		// A file where there is a directory, or the other way around, cannot
+		// be moved into place by any policy.
+		existing, e := os.Stat(outDir + PS + outPath)
+		if isDir := e == nil && existing.IsDir(); isDir != fi.IsDir() {
+			collisions = append(collisions, outPath)
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !fi.IsDir() {
+			conflicts = append(conflicts, outPath)
+		}
+
+		return nil
+	})
+	if e1 != nil {
+		return nil, e1
+	}
+
+	if len(collisions) > 0 {
+		return nil, fmt.Errorf(msg.Stderr.OutputTypeCollision, outDir, strings.Join(collisions, ", "))
+	}
+
+	sort.Strings(conflicts)
+
+	var failed []string
+
+	for _, outPath := range conflicts {
+		policy := conflictPolicy(rules, outPath)
+
+		if policy == ConflictPrompt {
+			policy = promptConflict(answers, outPath)
+		}
+
+		if policy == ConflictFail {
+			failed = append(failed, outPath)
+		}
+
+		log.Infof(msg.Stdout.Conflict, outPath, policy)
+		policies[outPath] = policy
+	}
+
+	if len(failed) > 0 {
+		return nil, fmt.Errorf(msg.Stderr.OutputExists, outDir, strings.Join(failed, ", "))
+	}
+
+	return policies, nil
+}
+
+// promptConflict Ask to overwrite, skip, or backup a file the output
+// directory already has, until the answer is one of them. The file is skipped
+// when there are no more answers.
+func promptConflict(answers *bufio.Scanner, outPath string) string {
+	for {
+		fmt.Printf(msg.Stdout.ConflictPrompt, outPath)
+
+		if !answers.Scan() {
+			return ConflictSkip
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answers.Text())) {
+		case "o", ConflictOverwrite:
+			return ConflictOverwrite
+		case "", "s", ConflictSkip:
+			return ConflictSkip
+		case "b", ConflictBackup:
+			return ConflictBackup
+		}
+	}
+}
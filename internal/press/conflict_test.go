@@ -0,0 +1,208 @@
+package press
+
+import (
+	"bufio"
+	"github.com/kohirens/stdlib/fsio"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseConflictRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *ConflictRule
+		wantErr bool
+	}{
+		{"policy", "skip", &ConflictRule{Policy: ConflictSkip}, false},
+		{"glob", ".github/*=overwrite", &ConflictRule{Files: ".github/*", Policy: ConflictOverwrite}, false},
+		{"glob-with-equals", "a=b/*=backup", &ConflictRule{Files: "a=b/*", Policy: ConflictBackup}, false},
+		{"unknown-policy", "merge", nil, true},
+		{"glob-unknown-policy", "*.md=merge", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := ParseConflictRule(tt.value)
+			if (e != nil) != tt.wantErr {
+				t.Fatalf("ParseConflictRule() error = %v, wantErr %v", e, tt.wantErr)
+			}
+
+			if tt.want != nil && *got != *tt.want {
+				t.Errorf("ParseConflictRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_conflictPolicy(t *testing.T) {
+	rules := []*ConflictRule{
+		{Policy: ConflictSkip},
+		{Files: ".github/*", Policy: ConflictOverwrite},
+		{Files: "*.md", Policy: ConflictBackup},
+		{Policy: ConflictFail},
+	}
+
+	tests := []struct {
+		name    string
+		rules   []*ConflictRule
+		outPath string
+		want    string
+	}{
+		{"no-rules", nil, "main.go", ConflictOverwrite},
+		{"last-without-glob", rules, "main.go", ConflictFail},
+		{"glob", rules, ".github/ci.yml", ConflictOverwrite},
+		{"second-glob", rules, "README.md", ConflictBackup},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conflictPolicy(tt.rules, tt.outPath); got != tt.want {
+				t.Errorf("conflictPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintConflicts(t *testing.T) {
+	fixture := fixtureDir + PS + "conflict-01"
+	existingA := "existing content of a, longer than the pressed one\n"
+	existingB := "existing b\n"
+
+	tests := []struct {
+		name    string
+		rules   []*ConflictRule
+		input   string
+		wantErr bool
+		want    map[string]string
+	}{
+		{"overwrite", []*ConflictRule{{Policy: ConflictOverwrite}}, "", false, map[string]string{
+			"a.txt": "a press\n", "sub/b.txt": "b press\n", "new.txt": "new\n",
+		}},
+		{"skip", []*ConflictRule{{Policy: ConflictSkip}}, "", false, map[string]string{
+			"a.txt": existingA, "sub/b.txt": existingB, "new.txt": "new\n",
+		}},
+		{"backup", []*ConflictRule{{Policy: ConflictBackup}}, "", false, map[string]string{
+			"a.txt": "a press\n", "a.txt.orig": existingA, "sub/b.txt.orig": existingB,
+		}},
+		{"fail", []*ConflictRule{{Policy: ConflictFail}}, "", true, map[string]string{
+			"a.txt": existingA, "sub/b.txt": existingB,
+		}},
+		{"glob", []*ConflictRule{{Policy: ConflictSkip}, {Files: "sub/*", Policy: ConflictOverwrite}}, "", false, map[string]string{
+			"a.txt": existingA, "sub/b.txt": "b press\n",
+		}},
+		{"prompt", []*ConflictRule{{Policy: ConflictPrompt}}, "maybe\no\n\n", false, map[string]string{
+			"a.txt": "a press\n", "sub/b.txt": existingB,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := tmpDir + PS + "conflict-" + tt.name
+
+			if e := os.MkdirAll(outDir+PS+"sub", dirMode); e != nil {
+				t.Fatal(e)
+			}
+			_ = os.WriteFile(outDir+PS+"a.txt", []byte(existingA), 0644)
+			_ = os.WriteFile(outDir+PS+"sub"+PS+"b.txt", []byte(existingB), 0644)
+
+			tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+			if e1 != nil {
+				t.Fatal(e1)
+			}
+
+			e2 := Print(fixture, outDir, map[string]string{"name": "press"}, tm, &PrintOptions{
+				Conflicts: tt.rules,
+				Answers:   bufio.NewScanner(strings.NewReader(tt.input)),
+			})
+			if (e2 != nil) != tt.wantErr {
+				t.Fatalf("Print() error = %v, wantErr %v", e2, tt.wantErr)
+			}
+
+			for file, want := range tt.want {
+				got, e := os.ReadFile(outDir + PS + file)
+				if e != nil {
+					t.Errorf("Print() did not output %v: %v", file, e)
+					continue
+				}
+
+				if string(got) != want {
+					t.Errorf("%v = %q, want %q", file, got, want)
+				}
+			}
+
+			if tt.wantErr && fsio.Exist(outDir+PS+"new.txt") {
+				t.Errorf("Print() output new.txt when a conflict failed")
+			}
+		})
+	}
+}
+
+func TestPromptsShareAnswers(t *testing.T) {
+	fixture := fixtureDir + PS + "conflict-01"
+	outDir := tmpDir + PS + "conflict-shared-answers"
+	existingB := "existing b\n"
+
+	if e := os.MkdirAll(outDir+PS+"sub", dirMode); e != nil {
+		t.Fatal(e)
+	}
+	_ = os.WriteFile(outDir+PS+"a.txt", []byte("existing a\n"), 0644)
+	_ = os.WriteFile(outDir+PS+"sub"+PS+"b.txt", []byte(existingB), 0644)
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	// Piped in all at once, as from a file, the scanner reads it all on the
+	// first prompt.
+	answers := bufio.NewScanner(strings.NewReader("acme\no\ns\n"))
+	vars := map[string]string{}
+
+	if e := GetPlaceholderInput(tm, vars, answers, " ", outDir); e != nil {
+		t.Fatal(e)
+	}
+
+	if vars["name"] != "acme" {
+		t.Fatalf("GetPlaceholderInput() name = %q, want acme", vars["name"])
+	}
+
+	e2 := Print(fixture, outDir, vars, tm, &PrintOptions{
+		Answers:   answers,
+		Conflicts: []*ConflictRule{{Policy: ConflictPrompt}},
+	})
+	if e2 != nil {
+		t.Fatalf("Print() error = %v", e2)
+	}
+
+	for file, want := range map[string]string{"a.txt": "a acme\n", "sub/b.txt": existingB} {
+		if got, _ := os.ReadFile(outDir + PS + file); string(got) != want {
+			t.Errorf("%v = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func Test_makeTruncates(t *testing.T) {
+	dstFile := tmpDir + PS + "truncate.txt"
+	sourcePath := tmpDir + PS + "truncate.tmpl"
+
+	if e := os.WriteFile(dstFile, []byte("a longer file that was there before\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := os.WriteFile(sourcePath, []byte("short\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+
+	p := &printer{tm: &TmplManifest{}}
+	op := &pressOp{dstFile: dstFile, kind: opPress, mode: 0644, relativePath: "truncate.txt", sourcePath: sourcePath}
+	if e := p.make(op); e != nil {
+		t.Fatal(e)
+	}
+
+	got, _ := os.ReadFile(dstFile)
+	if string(got) != "short\n" {
+		t.Errorf("make() = %q, want the file replaced", got)
+	}
+}
@@ -0,0 +1,129 @@
+package press
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kohirens/stdlib/log"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DataKey The name the data files are given in a template, next to the
+// placeholders, such as {{ .Data.regions }}.
+const DataKey = "Data"
+
+// LoadData Read the data files of a template, then those given on the command
+// line, each named by its file name without the extension. The data files of
+// the template are relative to it, and a file given on the command line
+// replaces one of the same name.
+func LoadData(tplDir string, tm *TmplManifest, files []string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	var paths []string
+	for _, file := range tm.Data {
+		path, e := inTemplate(tplDir, "data", file)
+		if e != nil {
+			return nil, e
+		}
+		paths = append(paths, path)
+	}
+	paths = append(paths, files...)
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+		v, e := readDataFile(path)
+		if e != nil {
+			return nil, e
+		}
+
+		if _, ok := data[name]; ok {
+			log.Infof(msg.Stdout.DataReplaced, name, path)
+		}
+
+		data[name] = v
+	}
+
+	return data, nil
+}
+
+// inTemplate Make a path from the manifest, relative to the template, into a
+// path in the template directory, so a manifest cannot read files outside of
+// it.
+func inTemplate(tplDir, property, file string) (string, error) {
+	path := filepath.Clean(tplDir + PS + file)
+	if !isInside(filepath.Clean(tplDir), path) {
+		return "", fmt.Errorf(msg.Stderr.PathOutsideTemplate, property, file)
+	}
+
+	return path, nil
+}
+
+// InData Indicates a file, relative to the root of the template, is one of
+// its data files.
+func (tm *TmplManifest) InData(relativePath string) bool {
+	for _, file := range tm.Data {
+		if filepath.ToSlash(filepath.Clean(file)) == filepath.ToSlash(relativePath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readDataFile Decode a JSON or YAML file, by its extension.
+func readDataFile(path string) (interface{}, error) {
+	content, e1 := os.ReadFile(path)
+	if e1 != nil {
+		return nil, fmt.Errorf(msg.Stderr.CannotReadFile, path, e1.Error())
+	}
+
+	var v interface{}
+	var e2 error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		e2 = json.Unmarshal(content, &v)
+	case ".yaml", ".yml":
+		v, e2 = parseYaml(content)
+	default:
+		return nil, fmt.Errorf(msg.Stderr.DataFileType, path)
+	}
+
+	if e2 != nil {
+		return nil, fmt.Errorf(msg.Stderr.DecodeDataFile, path, e2.Error())
+	}
+
+	return v, nil
+}
+
+// templateData The data a template is executed with, the placeholders, the
+// data files, and the metadata.
+func templateData(vars, types map[string]string, data map[string]interface{}, meta *Metadata) (map[string]interface{}, error) {
+	res := make(map[string]interface{}, len(vars)+2)
+	for k, v := range vars {
+		val, e := typedValue(k, types[k], v)
+		if e != nil {
+			return nil, e
+		}
+		res[k] = val
+	}
+
+	res[DataKey] = data
+	res[MetaKey] = meta
+
+	return res, nil
+}
+
+// checkData Verify the data files exist, and can be read.
+func checkData(filename string, files []string) error {
+	for _, file := range files {
+		if _, e := readDataFile(filepath.Dir(filename) + PS + file); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
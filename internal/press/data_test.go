@@ -0,0 +1,113 @@
+package press
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadData(t *testing.T) {
+	fixture := fixtureDir + PS + "data-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	tests := []struct {
+		name      string
+		files     []string
+		wantOwner string
+		wantErr   bool
+	}{
+		{"template", nil, "payments-team", false},
+		{"replaced", []string{fixtureDir + PS + "data-override" + PS + "owners.json"}, "platform-team", false},
+		{"not-found", []string{fixtureDir + PS + "missing.json"}, "", true},
+		{"not-json-or-yaml", []string{fixtureDir + PS + "dir-to-dir-03.bundle"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadData(fixture, tm, tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			owners, _ := got["owners"].(map[string]interface{})
+			if owners["billing"] != tt.wantOwner {
+				t.Errorf("LoadData() got owner %v, want %v", owners["billing"], tt.wantOwner)
+			}
+
+			if _, ok := got["regions"]; !ok {
+				t.Errorf("LoadData() want the regions data")
+			}
+		})
+	}
+}
+
+func TestLoadDataOutside(t *testing.T) {
+	fixture := fixtureDir + PS + "data-01"
+
+	for _, file := range []string{"../data-override/owners.json", "data/../../data-override/owners.json"} {
+		t.Run(file, func(t *testing.T) {
+			tm := &TmplManifest{Data: []string{file}}
+
+			if _, e := LoadData(fixture, tm, nil); e == nil {
+				t.Errorf("LoadData() want an error for a data file outside of the template")
+			}
+		})
+	}
+}
+
+func TestInData(t *testing.T) {
+	tm := &TmplManifest{Data: []string{"data/regions.yaml", "./owners.json"}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"data/regions.yaml", true},
+		{"owners.json", true},
+		{"data/other.yaml", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := tm.InData(tt.path); got != tt.want {
+				t.Errorf("InData() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintData(t *testing.T) {
+	fixture := fixtureDir + PS + "data-01"
+	outDir := tmpDir + PS + "data-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	vars := map[string]string{"appName": "billing", "region": "eu-west"}
+	files := []string{fixtureDir + PS + "data-override" + PS + "owners.json"}
+
+	if e := Print(fixture, outDir, vars, tm, &PrintOptions{DataFiles: files}); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	got, _ := os.ReadFile(outDir + PS + "deploy.yaml")
+	want := "app: billing\nendpoint: https://eu-west.example.com\nowner: platform-team\n"
+	if string(got) != want {
+		t.Errorf("deploy.yaml got %q, want %q", got, want)
+	}
+
+	for _, file := range []string{"data/regions.yaml", "owners.json"} {
+		if _, e := os.Stat(outDir + PS + file); e == nil {
+			t.Errorf("%v should not be output", file)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sources of a default placeholder value, other than the value itself.
+const (
+	defaultEnv    = "env:"
+	defaultGit    = "git:"
+	defaultOutDir = "outDir"
+)
+
+// ResolveDefault The default value of a placeholder from its source: a key of
+// the git config of the user, such as "git:user.name", an environment
+// variable, such as "env:GITHUB_ORG", or "outDir" for the name of the output
+// directory. Any other source is the value itself. The value is empty when
+// the source has none.
+func ResolveDefault(source, outDir string) string {
+	switch {
+	case strings.HasPrefix(source, defaultGit):
+		return GitConfig(strings.TrimPrefix(source, defaultGit))
+	case strings.HasPrefix(source, defaultEnv):
+		return os.Getenv(strings.TrimPrefix(source, defaultEnv))
+	case source == defaultOutDir:
+		dir, e := filepath.Abs(outDir)
+		if e != nil {
+			return ""
+		}
+		return filepath.Base(dir)
+	}
+
+	return source
+}
+
+// checkDefaults Verify each default is for a placeholder.
+func checkDefaults(placeholders, defaults map[string]string) error {
+	for name := range defaults {
+		if _, ok := placeholders[name]; !ok {
+			return fmt.Errorf(msg.Stderr.NoPlaceholder, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package press
+
+import (
+	"bufio"
+	"os"
+	"testing"
+)
+
+func TestResolveDefault(t *testing.T) {
+	t.Setenv("TMPLPRESS_TEST_ORG", "kohirens")
+
+	tests := []struct {
+		name   string
+		source string
+		outDir string
+		want   string
+	}{
+		{"env", "env:TMPLPRESS_TEST_ORG", "", "kohirens"},
+		{"env-unset", "env:TMPLPRESS_TEST_UNSET", "", ""},
+		{"git-unset", "git:tmplpress.test-unset", "", ""},
+		{"out-dir", "outDir", "/projects/billing-api", "billing-api"},
+		{"out-dir-slash", "outDir", "/projects/billing-api/", "billing-api"},
+		{"literal", "MIT", "", "MIT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveDefault(tt.source, tt.outDir); got != tt.want {
+				t.Errorf("ResolveDefault() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkDefaults(t *testing.T) {
+	placeholders := map[string]string{"appName": "Name of the app"}
+
+	tests := []struct {
+		name     string
+		defaults map[string]string
+		wantErr  bool
+	}{
+		{"none", nil, false},
+		{"placeholder", map[string]string{"appName": "outDir"}, false},
+		{"not-a-placeholder", map[string]string{"owner": "git:user.name"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if e := checkDefaults(placeholders, tt.defaults); (e != nil) != tt.wantErr {
+				t.Errorf("checkDefaults() error = %v, wantErr %v", e, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetPlaceholderInputDefaults(t *testing.T) {
+	t.Setenv("TMPLPRESS_TEST_ORG", "kohirens")
+
+	tests := []struct {
+		name       string
+		input      string
+		defaultVal string
+		want       string
+	}{
+		{"empty-input-takes-default", "\n", " ", "kohirens"},
+		{"input-wins", "acme\n", " ", "acme"},
+		{"non-interactive", "", "n/a", "kohirens"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, e1 := os.CreateTemp(t.TempDir(), "stdin")
+			if e1 != nil {
+				t.Fatal(e1)
+			}
+			defer r.Close()
+
+			_, _ = r.WriteString(tt.input)
+			_, _ = r.Seek(0, 0)
+
+			tm := &TmplManifest{
+				Defaults:     map[string]string{"org": "env:TMPLPRESS_TEST_ORG"},
+				Placeholders: map[string]string{"org": "GitHub organization", "license": "License"},
+			}
+			values := map[string]string{"license": "MIT"}
+
+			if e := GetPlaceholderInput(tm, values, bufio.NewScanner(r), tt.defaultVal, "out"); e != nil {
+				t.Fatal(e)
+			}
+
+			if values["org"] != tt.want {
+				t.Errorf("GetPlaceholderInput() org = %q, want %q", values["org"], tt.want)
+			}
+		})
+	}
+}
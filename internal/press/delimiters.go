@@ -0,0 +1,58 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+)
+
+// Delimiters Alternate delimiters of actions, for files full of "{{" and "}}"
+// that are not actions, such as Helm charts. They apply to the files that
+// match the glob patterns, or to all other files when there are none.
+type Delimiters struct {
+	Files []string `json:"files,omitempty"`
+	Left  string   `json:"left"`
+	Right string   `json:"right"`
+}
+
+// DelimsFor The delimiters to parse a file with. The first entry with
+// patterns that match the file is used, then the entry without any patterns.
+// Empty strings mean the default delimiters.
+func (tm *TmplManifest) DelimsFor(relativePath string) (string, string) {
+	var global *Delimiters
+
+	for _, d := range tm.Delimiters {
+		if len(d.Files) == 0 {
+			if global == nil {
+				global = d
+			}
+			continue
+		}
+
+		if InSkipArray(relativePath, d.Files) {
+			return d.Left, d.Right
+		}
+	}
+
+	if global != nil {
+		return global.Left, global.Right
+	}
+
+	return "", ""
+}
+
+// checkDelimiters Verify there is only one entry for all files.
+func checkDelimiters(delimiters []*Delimiters) error {
+	global := 0
+
+	for _, d := range delimiters {
+		if len(d.Files) == 0 {
+			global++
+		}
+	}
+
+	if global > 1 {
+		return fmt.Errorf(msg.Stderr.DelimitersGlobal, global)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package press
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDelimsFor(t *testing.T) {
+	tm := &TmplManifest{
+		Delimiters: []*Delimiters{
+			{Files: []string{"chart/*"}, Left: "[[", Right: "]]"},
+			{Left: "<%", Right: "%>"},
+			{Files: []string{"*.yaml"}, Left: "((", Right: "))"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantLeft  string
+		wantRight string
+	}{
+		{"first-match", "chart/values.yaml", "[[", "]]"},
+		{"later-match", "ci.yaml", "((", "))"},
+		{"all-other-files", "README.md", "<%", "%>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := tm.DelimsFor(tt.path)
+			if left != tt.wantLeft || right != tt.wantRight {
+				t.Errorf("DelimsFor() got %v %v, want %v %v", left, right, tt.wantLeft, tt.wantRight)
+			}
+		})
+	}
+
+	if left, right := (&TmplManifest{}).DelimsFor("README.md"); left != "" || right != "" {
+		t.Errorf("DelimsFor() got %v %v, want the default", left, right)
+	}
+}
+
+func Test_checkDelimiters(t *testing.T) {
+	twoGlobal := []*Delimiters{{Left: "[[", Right: "]]"}, {Left: "<%", Right: "%>"}}
+	if e := checkDelimiters(twoGlobal); e == nil {
+		t.Errorf("checkDelimiters() want an error for 2 entries without files")
+	}
+
+	oneGlobal := []*Delimiters{{Left: "[[", Right: "]]"}, {Files: []string{"*.md"}, Left: "<%", Right: "%>"}}
+	if e := checkDelimiters(oneGlobal); e != nil {
+		t.Errorf("checkDelimiters() error = %v", e)
+	}
+}
+
+func TestPrintDelimiters(t *testing.T) {
+	fixture := fixtureDir + PS + "delims-01"
+	outDir := tmpDir + PS + "delims-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	if e := Print(fixture, outDir, map[string]string{"appName": "billing"}, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"chart/templates/deployment.yaml", "name: billing\nimage: {{ .Values.image }}\n"},
+		{"README.md", "# billing\n"},
+		{"notes.txt", "billing {{ not an action }}\n"},
+	}
+
+	for _, tt := range tests {
+		got, _ := os.ReadFile(outDir + PS + tt.file)
+		if string(got) != tt.want {
+			t.Errorf("%v got %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
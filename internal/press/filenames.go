@@ -0,0 +1,53 @@
+package press
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// dotPrefix What a prefix of Filenames.DotPrefixes is replaced with.
+const dotPrefix = "."
+
+// Filenames Transforms of the names of the files and directories of a
+// template, for files that would otherwise have an effect on the template
+// repository itself, such as ".gitignore" or ".circleci".
+type Filenames struct {
+	// DotPrefixes A prefix, such as "dot_", that is replaced with a "." at
+	// the start of the name of a file or directory.
+	DotPrefixes []string `json:"dotPrefixes,omitempty"`
+
+	// StripSuffixes A suffix, such as ".tmpl", that is removed from the end
+	// of the name of a file.
+	StripSuffixes []string `json:"stripSuffixes,omitempty"`
+}
+
+// OutputPath The path a file of the template is output to, with the
+// transforms of Filenames applied, before its placeholders are filled in.
+// The first prefix, and suffix, that a name has is the one applied, and never
+// when it is the whole name.
+func (tm *TmplManifest) OutputPath(relativePath string) string {
+	if tm.Filenames == nil {
+		return relativePath
+	}
+
+	names := strings.Split(filepath.ToSlash(relativePath), "/")
+	last := len(names) - 1
+
+	for _, suffix := range tm.Filenames.StripSuffixes {
+		if len(names[last]) > len(suffix) && strings.HasSuffix(names[last], suffix) {
+			names[last] = strings.TrimSuffix(names[last], suffix)
+			break
+		}
+	}
+
+	for i, name := range names {
+		for _, prefix := range tm.Filenames.DotPrefixes {
+			if len(name) > len(prefix) && strings.HasPrefix(name, prefix) {
+				names[i] = dotPrefix + strings.TrimPrefix(name, prefix)
+				break
+			}
+		}
+	}
+
+	return filepath.FromSlash(strings.Join(names, "/"))
+}
@@ -0,0 +1,100 @@
+package press
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTmplManifest_OutputPath(t *testing.T) {
+	tm := &TmplManifest{Filenames: &Filenames{
+		DotPrefixes:   []string{"dot_", "_"},
+		StripSuffixes: []string{".tmpl", ".tpl"},
+	}}
+
+	tests := []struct {
+		name string
+		tm   *TmplManifest
+		path string
+		want string
+	}{
+		{"none", &TmplManifest{}, "dot_gitignore.tmpl", "dot_gitignore.tmpl"},
+		{"dot-prefix", tm, "dot_gitignore", ".gitignore"},
+		{"second-prefix", tm, "_editorconfig", ".editorconfig"},
+		{"first-prefix-only", tm, "dot__env", "._env"},
+		{"directory", tm, "dot_circleci/config.yml", ".circleci/config.yml"},
+		{"suffix", tm, "go.mod.tmpl", "go.mod"},
+		{"first-suffix-only", tm, "a.tpl.tmpl", "a.tpl"},
+		{"suffix-of-file-only", tm, "docs.tmpl/a.md", "docs.tmpl/a.md"},
+		{"both", tm, "dot_github/dot_env.tpl", ".github/.env"},
+		{"whole-name", tm, "dot_/.tmpl", "dot_/.tmpl"},
+		{"placeholder", tm, "dot_{{.name}}.tmpl", ".{{.name}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tm.OutputPath(filepath.FromSlash(tt.path)); got != filepath.FromSlash(tt.want) {
+				t.Errorf("OutputPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintFilenames(t *testing.T) {
+	fixture := fixtureDir + PS + "filenames-01"
+	outDir := tmpDir + PS + "filenames-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	if e := Print(fixture, outDir, map[string]string{"module": "example.com/app"}, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"dot-prefix", ".gitignore", "tmp/\n"},
+		{"dot-prefix-dir", ".circleci/config.yml", "version: 2.1\n"},
+		{"suffix", "go.mod", "module example.com/app\n"},
+		{"unchanged", "README.md", "# example.com/app\n"},
+		{"other-prefix", "_config.yml", "keep\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := os.ReadFile(outDir + PS + filepath.FromSlash(tt.file))
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintFilenamesCollision(t *testing.T) {
+	fixture := tmpDir + PS + "filenames-collision-01"
+	if e := os.MkdirAll(fixture, dirMode); e != nil {
+		t.Fatal(e)
+	}
+	for _, name := range []string{".gitignore", "dot_gitignore"} {
+		if e := os.WriteFile(fixture+PS+name, []byte("tmp/\n"), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	tm := &TmplManifest{Filenames: &Filenames{DotPrefixes: []string{"dot_"}}, Version: SchemaVersion}
+
+	err := Print(fixture, tmpDir+PS+"filenames-collision-01-out", nil, tm, nil)
+	if err == nil || !strings.Contains(err.Error(), "would both be output to .gitignore") {
+		t.Errorf("Print() error = %v, want a collision", err)
+	}
+}
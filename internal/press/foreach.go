@@ -0,0 +1,103 @@
+package press
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"strings"
+)
+
+// defaultItemName The name an item of a list is given in a for-each file,
+// when the manifest does not give one.
+const defaultItemName = "item"
+
+// ForEach Files pressed once for each item of a list placeholder, each to a
+// path made from the item, such as one file per service.
+type ForEach struct {
+	// As The name of the item in the file and path, "item" by default.
+	As string `json:"as,omitempty"`
+
+	// Files Patterns of the files to press for each item.
+	Files []string `json:"files"`
+
+	// List The placeholder with the list of items.
+	List string `json:"list"`
+
+	// Path A template of the path to output each item to.
+	Path string `json:"path"`
+}
+
+// ItemName The name each item is given in the template.
+func (fe *ForEach) ItemName() string {
+	if fe.As == "" {
+		return defaultItemName
+	}
+
+	return fe.As
+}
+
+// ForEachFor The first for-each entry with patterns that match the file, or
+// nil when the file is pressed once.
+func (tm *TmplManifest) ForEachFor(relativePath string) *ForEach {
+	for _, fe := range tm.ForEach {
+		if InSkipArray(relativePath, fe.Files) {
+			return fe
+		}
+	}
+
+	return nil
+}
+
+// ListItems The items of the value of a list placeholder. A value that starts
+// with "[" is a JSON array, so items can be objects, otherwise it is a comma
+// separated list of strings.
+func ListItems(value string) ([]interface{}, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	var items []interface{}
+
+	if strings.HasPrefix(value, "[") {
+		if e := json.Unmarshal([]byte(value), &items); e != nil {
+			return nil, fmt.Errorf(msg.Stderr.ListItems, e.Error())
+		}
+		return items, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		items = append(items, strings.TrimSpace(item))
+	}
+
+	return items, nil
+}
+
+// itemData The data of a template, with an item of a list added under its
+// name.
+func itemData(ctx map[string]interface{}, name string, item interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(ctx)+1)
+	for k, v := range ctx {
+		data[k] = v
+	}
+
+	data[name] = item
+
+	return data
+}
+
+// checkForEach Verify each list is a placeholder, and the name of the items
+// does not hide one.
+func checkForEach(placeholders map[string]string, forEach []*ForEach) error {
+	for _, fe := range forEach {
+		if _, ok := placeholders[fe.List]; !ok {
+			return fmt.Errorf(msg.Stderr.NoPlaceholder, fe.List)
+		}
+
+		if _, ok := placeholders[fe.ItemName()]; ok {
+			return fmt.Errorf(msg.Stderr.ForEachItemName, fe.ItemName())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package press
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListItems(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []interface{}
+		wantErr bool
+	}{
+		{"empty", " ", nil, false},
+		{"comma-separated", "en, fr,de", []interface{}{"en", "fr", "de"}, false},
+		{"json-strings", `["en", "fr"]`, []interface{}{"en", "fr"}, false},
+		{"json-objects", `[{"name": "api"}]`, []interface{}{map[string]interface{}{"name": "api"}}, false},
+		{"invalid-json", `[{"name": "api"`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ListItems(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListItems() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListItems() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForEachFor(t *testing.T) {
+	tm := &TmplManifest{
+		ForEach: []*ForEach{
+			{Files: []string{"services/*"}, List: "services", As: "service"},
+			{Files: []string{"*.txt"}, List: "locales"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantList string
+		wantItem string
+	}{
+		{"named-item", "services/service.yaml", "services", "service"},
+		{"default-item-name", "locale.txt", "locales", "item"},
+		{"not-for-each", "README.md", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fe := tm.ForEachFor(tt.path)
+			if fe == nil {
+				if tt.wantList != "" {
+					t.Fatalf("ForEachFor() got nil, want %v", tt.wantList)
+				}
+				return
+			}
+
+			if fe.List != tt.wantList || fe.ItemName() != tt.wantItem {
+				t.Errorf("ForEachFor() got %v %v, want %v %v", fe.List, fe.ItemName(), tt.wantList, tt.wantItem)
+			}
+		})
+	}
+}
+
+func Test_checkForEach(t *testing.T) {
+	placeholders := map[string]string{"services": "", "item": ""}
+
+	tests := []struct {
+		name    string
+		forEach []*ForEach
+		wantErr bool
+	}{
+		{"ok", []*ForEach{{List: "services", As: "service"}}, false},
+		{"no-placeholder", []*ForEach{{List: "locales", As: "locale"}}, true},
+		{"item-hides-placeholder", []*ForEach{{List: "services"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkForEach(placeholders, tt.forEach); (err != nil) != tt.wantErr {
+				t.Errorf("checkForEach() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrintForEach(t *testing.T) {
+	fixture := fixtureDir + PS + "foreach-01"
+	outDir := tmpDir + PS + "foreach-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	vars := map[string]string{
+		"appName":  "billing",
+		"locales":  "en,fr",
+		"services": `[{"name": "api", "port": 8080}, {"name": "worker", "port": 9090}]`,
+	}
+
+	if e := Print(fixture, outDir, vars, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"services/api.yaml", "app: billing\nname: api\nport: 8080\n"},
+		{"services/worker.yaml", "app: billing\nname: worker\nport: 9090\n"},
+		{"locales/en.txt", "billing in en\n"},
+		{"locales/fr.txt", "billing in fr\n"},
+		{"README.md", "# billing\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, _ := os.ReadFile(outDir + PS + tt.file)
+			if string(got) != tt.want {
+				t.Errorf("%v got %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+
+	for _, file := range []string{"service.yaml", "locale.txt"} {
+		if _, e := os.Stat(outDir + PS + file); e == nil {
+			t.Errorf("%v should not be output", file)
+		}
+	}
+
+	// Items that make the same path are reported, rather than overwritten.
+	vars["locales"] = "en,fr,en"
+	if e := Print(fixture, tmpDir+PS+"foreach-01-collision", vars, tm, nil); e == nil {
+		t.Errorf("Print() want an error for 2 items output to the same path")
+	}
+
+	// An item cannot put a file outside of the output directory.
+	vars["locales"] = "en,../../../escaped"
+	traversal := tmpDir + PS + "foreach-01-traversal" + PS + "out"
+	if e := Print(fixture, traversal, vars, tm, nil); e == nil || !strings.Contains(e.Error(), "locale.txt") {
+		t.Errorf("Print() error = %v, want an error naming locale.txt for an item outside of the output", e)
+	}
+
+	if escaped, _ := filepath.Glob(tmpDir + PS + "escaped*"); len(escaped) > 0 {
+		t.Errorf("Print() wrote outside of the output directory: %v", escaped)
+	}
+}
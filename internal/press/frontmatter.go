@@ -0,0 +1,147 @@
+package press
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// frontMatterStart The first line of a file with front matter.
+	frontMatterStart = "---tmplpress"
+	// frontMatterEnd The line that ends the front matter.
+	frontMatterEnd = "---"
+)
+
+// FrontMatter Settings for a single file, in a JSON header at the top of the
+// file, that is removed before the file is pressed:
+//
+//	---tmplpress
+//	{"path": "cmd/{{.appName}}/main.go", "mode": "0755"}
+//	---
+type FrontMatter struct {
+	// Delimiters to parse the file with, in place of those in the manifest.
+	Delimiters *Delimiters `json:"delimiters,omitempty"`
+
+	// If A template that includes the file in the output, unless it renders
+	// an empty string, "false", or "0".
+	If string `json:"if,omitempty"`
+
+	// Mode An octal file mode of the output, such as "0755".
+	Mode string `json:"mode,omitempty"`
+
+	// Path A template of the path to output the file to, relative to the
+	// output directory.
+	Path string `json:"path,omitempty"`
+
+	// Raw Output the file as it is, without parsing it as a template.
+	Raw bool `json:"raw,omitempty"`
+}
+
+// ReadFrontMatter Split the front matter from the content of a file. The front
+// matter is nil when the file does not have any.
+func ReadFrontMatter(content []byte) (*FrontMatter, []byte, error) {
+	first, rest, found := bytes.Cut(content, []byte("\n"))
+	if !found || string(bytes.TrimRight(first, "\r")) != frontMatterStart {
+		return nil, content, nil
+	}
+
+	var header []byte
+	for {
+		var line []byte
+		line, rest, found = bytes.Cut(rest, []byte("\n"))
+
+		if string(bytes.TrimRight(line, "\r")) == frontMatterEnd {
+			break
+		}
+
+		if !found {
+			return nil, nil, fmt.Errorf(msg.Stderr.FrontMatterNoEnd, frontMatterEnd)
+		}
+
+		header = append(header, line...)
+		header = append(header, '\n')
+	}
+
+	fm := &FrontMatter{}
+
+	dec := json.NewDecoder(bytes.NewReader(header))
+	dec.DisallowUnknownFields()
+	if e := dec.Decode(fm); e != nil {
+		return nil, nil, fmt.Errorf(msg.Stderr.FrontMatterDecode, e.Error())
+	}
+
+	if fm.Delimiters != nil && (fm.Delimiters.Left == "" || fm.Delimiters.Right == "") {
+		return nil, nil, fmt.Errorf(msg.Stderr.FrontMatterDelims)
+	}
+
+	return fm, rest, nil
+}
+
+// FileDelims The delimiters to parse a file with, those in its front matter
+// take precedence over the manifest.
+func (tm *TmplManifest) FileDelims(relativePath string, fm *FrontMatter) (string, string) {
+	if fm != nil && fm.Delimiters != nil {
+		return fm.Delimiters.Left, fm.Delimiters.Right
+	}
+
+	return tm.DelimsFor(relativePath)
+}
+
+// Include Indicates the file is output, as decided by its condition.
+func (fm *FrontMatter) Include(data interface{}) (bool, error) {
+	if fm.If == "" {
+		return true, nil
+	}
+
+	res, e := renderText(fm.If, data)
+	if e != nil {
+		return false, fmt.Errorf(msg.Stderr.FrontMatterIf, fm.If, e.Error())
+	}
+
+	switch strings.TrimSpace(res) {
+	case "", "false", "0":
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// FileMode The mode of the output file, when one is set.
+func (fm *FrontMatter) FileMode() (os.FileMode, bool, error) {
+	if fm.Mode == "" {
+		return 0, false, nil
+	}
+
+	mode, ok := parseFileMode(fm.Mode)
+	if !ok {
+		return 0, false, fmt.Errorf(msg.Stderr.FrontMatterMode, fm.Mode)
+	}
+
+	return mode, true, nil
+}
+
+// OutPath The path to output the file to, relative to the output directory,
+// or the one given when there is no path set.
+func (fm *FrontMatter) OutPath(outPath string, data interface{}) (string, error) {
+	if fm.Path == "" {
+		return outPath, nil
+	}
+
+	p, e := renderPath(fm.Path, data)
+	if e != nil {
+		return "", e
+	}
+
+	// Keep the file in the output directory.
+	p = filepath.Clean(filepath.FromSlash(p))
+	if escapesOutDir(p) {
+		return "", fmt.Errorf(msg.Stderr.FrontMatterPath, fm.Path)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,190 @@
+package press
+
+import (
+	"os"
+	"testing"
+)
+
+func TestReadFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantPath string
+		wantBody string
+		wantNil  bool
+		wantErr  bool
+	}{
+		{"none", "{{.appName}}\n", "", "{{.appName}}\n", true, false},
+		{"path", "---tmplpress\n{\"path\": \"a.txt\"}\n---\nbody\n", "a.txt", "body\n", false, false},
+		{"crlf", "---tmplpress\r\n{\"path\": \"a.txt\"}\r\n---\r\nbody\r\n", "a.txt", "body\r\n", false, false},
+		{"empty-body", "---tmplpress\n{}\n---", "", "", false, false},
+		{"not-first-line", "\n---tmplpress\n{}\n---\n", "", "\n---tmplpress\n{}\n---\n", true, false},
+		{"no-end", "---tmplpress\n{}\n", "", "", false, true},
+		{"unknown-field", "---tmplpress\n{\"output\": \"a.txt\"}\n---\n", "", "", false, true},
+		{"half-delimiters", "---tmplpress\n{\"delimiters\": {\"left\": \"[[\"}}\n---\n", "", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := ReadFrontMatter([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadFrontMatter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if (fm == nil) != tt.wantNil {
+				t.Fatalf("ReadFrontMatter() got %v, wantNil %v", fm, tt.wantNil)
+			}
+
+			if fm != nil && fm.Path != tt.wantPath {
+				t.Errorf("ReadFrontMatter() path got %q, want %q", fm.Path, tt.wantPath)
+			}
+
+			if string(body) != tt.wantBody {
+				t.Errorf("ReadFrontMatter() body got %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestFrontMatter_Include(t *testing.T) {
+	tests := []struct {
+		name    string
+		cond    string
+		vars    map[string]string
+		want    bool
+		wantErr bool
+	}{
+		{"no-condition", "", nil, true, false},
+		{"true", "{{.withDocker}}", map[string]string{"withDocker": "true"}, true, false},
+		{"false", "{{.withDocker}}", map[string]string{"withDocker": "false"}, false, false},
+		{"zero", "{{.count}}", map[string]string{"count": " 0 "}, false, false},
+		{"empty", "{{if eq .db \"sqlite\"}}yes{{end}}", map[string]string{"db": "postgres"}, false, false},
+		{"bad-template", "{{.withDocker", nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&FrontMatter{If: tt.cond}).Include(tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Include() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Include() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrontMatter_OutPath(t *testing.T) {
+	vars := map[string]string{"appName": "billing"}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"unset", "", "main.go", false},
+		{"placeholder", "cmd/{{.appName}}/main.go", "cmd" + PS + "billing" + PS + "main.go", false},
+		{"absolute", "/etc/passwd", "", true},
+		{"escapes", "../{{.appName}}.go", "", true},
+		{"output-dir", "cmd/..", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&FrontMatter{Path: tt.path}).OutPath("main.go", vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OutPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("OutPath() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrontMatter_FileMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    os.FileMode
+		wantSet bool
+		wantErr bool
+	}{
+		{"unset", "", 0, false, false},
+		{"executable", "0755", 0755, true, false},
+		{"no-leading-zero", "640", 0640, true, false},
+		{"not-octal", "0789", 0, false, true},
+		{"too-big", "01777", 0, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, set, err := (&FrontMatter{Mode: tt.mode}).FileMode()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FileMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want || set != tt.wantSet {
+				t.Errorf("FileMode() got %v %v, want %v %v", got, set, tt.want, tt.wantSet)
+			}
+		})
+	}
+}
+
+func TestPrintFrontMatter(t *testing.T) {
+	fixture := fixtureDir + PS + "frontmatter-01"
+	outDir := tmpDir + PS + "frontmatter-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	vars := map[string]string{"appName": "billing", "withDocker": "false"}
+	if e := Print(fixture, outDir, vars, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"path", "cmd/billing/main.go", "package main // billing\n\nfunc main() {}\n"},
+		{"raw", "notes.md", "Run {{ .appName }}\n"},
+		{"delimiters", "values.yaml", "name: billing\nimage: {{ .Values.image }}\n"},
+		{"copy-as-is-wins", "as-is.txt", "---tmplpress\n{\"path\": \"moved.txt\"}\n---\n{{.appName}}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := os.ReadFile(outDir + PS + tt.file)
+			if string(got) != tt.want {
+				t.Errorf("%v got %q, want %q", tt.file, got, tt.want)
+			}
+		})
+	}
+
+	for _, file := range []string{"main.go", "Dockerfile", "moved.txt"} {
+		if _, e := os.Stat(outDir + PS + file); e == nil {
+			t.Errorf("%v should not be output", file)
+		}
+	}
+
+	fi, e2 := os.Stat(outDir + PS + "cmd/billing/main.go")
+	if e2 != nil {
+		t.Fatal(e2)
+	}
+
+	if fi.Mode().Perm() != 0750 {
+		t.Errorf("cmd/billing/main.go mode got %v, want %v", fi.Mode().Perm(), os.FileMode(0750))
+	}
+}
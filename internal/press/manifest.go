@@ -10,8 +10,12 @@ import (
 )
 
 const (
-	schemaVersion    = "2.2.0"
+	// SchemaVersion The version of the template manifest schema this program
+	// supports, it must match the "version" in template.schema.json.
+	SchemaVersion    = "2.15.0"
+	SchemaUrl        = "https://github.com/kohirens/tmplpress/blob/main/template.schema.json"
 	TmplManifestFile = "template.json"
+	upgradeHint      = "go install github.com/kohirens/tmplpress@latest"
 )
 
 type AnswersJson struct {
@@ -23,14 +27,52 @@ type TmplManifest struct {
 	// but still are output in the final output.
 	CopyAsIs []string `json:"copyAsIs,omitempty"`
 
+	// Data JSON or YAML files, relative to the template, given to every file
+	// as .Data. The files are not output.
+	Data []string `json:"data,omitempty"`
+
+	// Defaults Where to get the default value of a placeholder, such as the
+	// git config of the user, offered when asking for its value.
+	Defaults map[string]string `json:"defaults,omitempty"`
+
+	// Delimiters to use in place of "{{" and "}}", for all or some files.
+	Delimiters []*Delimiters `json:"delimiters,omitempty"`
+
 	// EmptyDirFile Name of a file that marks a directory as empty and has the
 	// effect of "mkdir -p". This file allows you to add directories to Git but
 	// have them made and empty when the template is pressed.
-	EmptyDirFile string `json:"emptyDirFile"`
+	EmptyDirFile string `json:"emptyDirFile,omitempty"`
+
+	// Filenames Transforms of the names of files, such as "dot_gitignore" to
+	// ".gitignore", when they are output.
+	Filenames *Filenames `json:"filenames,omitempty"`
+
+	// ForEach Files to press once for each item of a list placeholder.
+	ForEach []*ForEach `json:"forEach,omitempty"`
+
+	// MaxRenderSize The largest file, in bytes, that is rendered as a
+	// template, see RenderLimit.
+	MaxRenderSize int64 `json:"maxRenderSize,omitempty"`
+
+	// MinPressVersion The oldest version of tmplpress that can press the
+	// template.
+	MinPressVersion string `json:"minPressVersion,omitempty"`
+
+	// Modes File modes of the output of the files that match, over those of
+	// the template files.
+	Modes []*Mode `json:"modes,omitempty"`
+
+	// Partials A directory of files with templates, made with "define", that
+	// every file can use. The directory is not output.
+	Partials string `json:"partials,omitempty"`
 
 	// Values to supply to the template to fill in variables.
 	Placeholders map[string]string `json:"placeholders,omitempty"`
 
+	// Render Patterns of files to press as templates, even when their content
+	// looks binary, which are otherwise copied as-is.
+	Render []string `json:"render,omitempty"`
+
 	// Files that should not be processed through the template engine nor added
 	// to the final output.
 	Skip []string `json:"skip,omitempty"`
@@ -40,8 +82,26 @@ type TmplManifest struct {
 	// Note that an empty directory can replace a directory with files.
 	Substitute string `json:"substitute,omitempty"`
 
+	// SuggestedValidation Validation suggested from the inferred types, for
+	// the designer to confirm by moving them to Validation. These are not
+	// applied.
+	SuggestedValidation []*validator `json:"suggestedValidation,omitempty"`
+
+	// Symlinks How symbolic links in the template are output, see
+	// SymlinksPreserve and SymlinksFollow.
+	Symlinks string `json:"symlinks,omitempty"`
+
+	// Text The encoding of files, and line endings of their output.
+	Text []*TextFormat `json:"text,omitempty"`
+
+	// Types of placeholders, inferred from how the template uses them.
+	Types map[string]string `json:"types,omitempty"`
+
 	// Optional validation to use when entering placeholder values from the CLI.
 	Validation []*validator `json:"validation,omitempty"`
+
+	// Version of the schema the manifest was written for.
+	Version string `json:"version"`
 }
 
 // LoadAnswers Load key/value pairs from a JSON file to fill in placeholders (provides that data for the Go templates).
@@ -61,9 +121,45 @@ func LoadAnswers(filename string) (*AnswersJson, error) {
 		return nil, fmt.Errorf(msg.Stderr.CannotDecodeAnswerFile, filename, e.Error())
 	}
 
+	errs, e2 := ValidateAnswersSchema(content)
+	if e2 != nil {
+		return nil, fmt.Errorf(msg.Stderr.CannotDecodeAnswerFile, filename, e2.Error())
+	}
+
+	if e := SchemaErrors(filename, errs); e != nil {
+		return nil, e
+	}
+
 	return aj, nil
 }
 
+// CheckManifestSchema Validate a template manifest against the schema, but
+// only when it declares the major version of the schema this program supports.
+// Older manifests are read by a compatibility decoder instead.
+func CheckManifestSchema(filePath string) error {
+	content, e1 := os.ReadFile(filePath)
+	if e1 != nil {
+		return fmt.Errorf(msg.Stderr.CannotReadFile, filePath, e1)
+	}
+
+	version, e2 := manifestVersion(content)
+	if e2 != nil {
+		return e2
+	}
+
+	if !IsCurrentManifestVersion(version) {
+		log.Infof(msg.Stdout.SchemaVersionSkip, filePath, version, schemaMajor())
+		return nil
+	}
+
+	errs, e3 := ValidateManifestSchema(content)
+	if e3 != nil {
+		return e3
+	}
+
+	return SchemaErrors(filePath, errs)
+}
+
 // ReadTemplateJson read variables needed from the template.json file.
 func ReadTemplateJson(filePath string) (*TmplManifest, error) {
 	log.Dbugf(msg.Stdout.TemplatePath, filePath)
@@ -89,11 +185,49 @@ func ReadTemplateJson(filePath string) (*TmplManifest, error) {
 	return q, nil
 }
 
+// NewTmplManifest Decode a template manifest, routing it through the decoder
+// for the version it declares.
 func NewTmplManifest(content []byte) (*TmplManifest, error) {
-	tmf := &TmplManifest{}
-	if e := json.Unmarshal(content, &tmf); e != nil {
-		return nil, fmt.Errorf(msg.Stderr.NewManifest, e.Error())
+	version, e1 := manifestVersion(content)
+	if e1 != nil {
+		return nil, e1
+	}
+
+	decode, e2 := selectDecoder(version)
+	if e2 != nil {
+		return nil, e2
+	}
+
+	tm, e3 := decode(content)
+	if e3 != nil {
+		return nil, e3
+	}
+
+	// They would be replaced by the data tmplpress gives every template, or
+	// hide it in a for-each file.
+	for name := range tm.Placeholders {
+		if IsReservedName(name) {
+			return nil, fmt.Errorf(msg.Stderr.ReservedName, name)
+		}
+	}
+
+	for _, fe := range tm.ForEach {
+		if IsReservedName(fe.ItemName()) {
+			return nil, fmt.Errorf(msg.Stderr.ForEachItemReserved, fe.ItemName())
+		}
+	}
+
+	return tm, nil
+}
+
+// manifestVersion Read only the version from the content of a manifest.
+func manifestVersion(content []byte) (string, error) {
+	v := struct {
+		Version string `json:"version"`
+	}{}
+	if e := json.Unmarshal(content, &v); e != nil {
+		return "", fmt.Errorf(msg.Stderr.NewManifest, e.Error())
 	}
 
-	return tmf, nil
+	return v.Version, nil
 }
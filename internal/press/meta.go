@@ -0,0 +1,116 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"net/mail"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetaKey The name the metadata is given in a template, next to the
+// placeholders, such as {{ .Press.Year }}.
+const MetaKey = "Press"
+
+// Metadata Facts about pressing a template, filled in by tmplpress, such as
+// the date and the commit of the template.
+type Metadata struct {
+	// Date pressed, such as 2024-01-02.
+	Date string
+
+	// Template that was pressed.
+	Template *TemplateMeta
+
+	// Time pressed, in RFC 3339 format.
+	Time string
+
+	// User pressing the template, from their git config.
+	User *UserMeta
+
+	// Version of tmplpress.
+	Version string
+
+	// Year pressed, such as 2024.
+	Year string
+}
+
+// TemplateMeta Where a template came from.
+type TemplateMeta struct {
+	// Commit hash of the template.
+	Commit string
+
+	// Name of the template, the last part of its source without ".git".
+	Name string
+
+	// Ref of the template, such as a branch or tag.
+	Ref string
+
+	// Source URL or path of the template.
+	Source string
+}
+
+// UserMeta The user pressing a template.
+type UserMeta struct {
+	Email string
+	Name  string
+}
+
+// NewMetadata Metadata of pressing a template at a time, and by a user, which
+// can be fixed so the output is the same each time. The template name is taken
+// from its source when it has none, and the user from their git config when
+// none is given.
+func NewMetadata(now time.Time, version string, tmpl *TemplateMeta, user *UserMeta) *Metadata {
+	if tmpl == nil {
+		tmpl = &TemplateMeta{}
+	}
+
+	if user == nil {
+		user = &UserMeta{
+			Email: GitConfig("user.email"),
+			Name:  GitConfig("user.name"),
+		}
+	}
+
+	if tmpl.Name == "" && tmpl.Source != "" {
+		tmpl.Name = strings.TrimSuffix(filepath.Base(filepath.ToSlash(tmpl.Source)), ".git")
+	}
+
+	return &Metadata{
+		Date:     now.Format(time.DateOnly),
+		Template: tmpl,
+		Time:     now.Format(time.RFC3339),
+		User:     user,
+		Version:  version,
+		Year:     strconv.Itoa(now.Year()),
+	}
+}
+
+// GitConfig The value of a key in the git config of the user, or an empty
+// string when it is not set, or git is not installed.
+func GitConfig(key string) string {
+	out, e := exec.Command("git", "config", "--get", key).Output()
+	if e != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// ParseUser A user written as in a git commit, such as "Ann <ann@example.com>".
+func ParseUser(value string) (*UserMeta, error) {
+	addr, e := mail.ParseAddress(value)
+	if e != nil {
+		return nil, fmt.Errorf(msg.Stderr.FixedUser, value, e.Error())
+	}
+
+	return &UserMeta{Email: addr.Address, Name: addr.Name}, nil
+}
+
+// IsReservedName Indicates a name is used by tmplpress for data it gives to
+// every template, so it cannot be a placeholder.
+func IsReservedName(name string) bool {
+	return name == DataKey || name == MetaKey
+}
@@ -0,0 +1,100 @@
+package press
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewMetadata(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		tmpl     *TemplateMeta
+		wantName string
+	}{
+		{"git-url", &TemplateMeta{Source: "https://github.com/kohirens/tmpl-go-web.git"}, "tmpl-go-web"},
+		{"local-path", &TemplateMeta{Source: "/templates/go-cli"}, "go-cli"},
+		{"given-name", &TemplateMeta{Name: "web", Source: "/templates/go-web"}, "web"},
+		{"no-template", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMetadata(now, "1.2.3", tt.tmpl, nil)
+
+			if got.Date != "2024-01-02" || got.Year != "2024" || got.Time != "2024-01-02T15:04:05Z" {
+				t.Errorf("NewMetadata() got %v %v %v, want the fixed time", got.Date, got.Year, got.Time)
+			}
+
+			if got.Version != "1.2.3" {
+				t.Errorf("NewMetadata() got version %v, want 1.2.3", got.Version)
+			}
+
+			if got.Template.Name != tt.wantName {
+				t.Errorf("NewMetadata() got name %q, want %q", got.Template.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestParseUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *UserMeta
+		wantErr bool
+	}{
+		{"name-and-email", "Ann Lee <ann@example.com>", &UserMeta{Email: "ann@example.com", Name: "Ann Lee"}, false},
+		{"email-only", "ann@example.com", &UserMeta{Email: "ann@example.com"}, false},
+		{"name-only", "Ann Lee", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUser(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUser() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMetadataFixedUser(t *testing.T) {
+	user := &UserMeta{Email: "ann@example.com", Name: "Ann Lee"}
+
+	if got := NewMetadata(time.Now(), "", nil, user); got.User != user {
+		t.Errorf("NewMetadata() got user %v, want %v", got.User, user)
+	}
+}
+
+func TestPrintMetadata(t *testing.T) {
+	fixture := fixtureDir + PS + "meta-01"
+	outDir := tmpDir + PS + "meta-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	meta := NewMetadata(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), "4.1.0", &TemplateMeta{
+		Commit: "abc123",
+		Source: "https://github.com/kohirens/tmpl-license.git",
+	}, nil)
+
+	if e := Print(fixture, outDir, map[string]string{"owner": "Kohirens"}, tm, &PrintOptions{Meta: meta}); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	got, _ := os.ReadFile(outDir + PS + "LICENSE")
+	want := "Copyright 2024 Kohirens\nMade from tmpl-license@abc123 by tmplpress 4.1.0 on 2024-01-02.\n"
+	if string(got) != want {
+		t.Errorf("LICENSE got %q, want %q", got, want)
+	}
+}
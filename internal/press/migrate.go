@@ -0,0 +1,157 @@
+package press
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/kohirens/stdlib/log"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"golang.org/x/mod/semver"
+	"os"
+)
+
+// migration Bring a manifest up to the version "to" from the one before it.
+// The manifest is worked on as a map so that any properties a migration does
+// not know about are carried over as they are.
+type migration struct {
+	to      string
+	migrate func(m map[string]interface{}) error
+}
+
+// migrations Ordered oldest to newest, a manifest goes through each one newer
+// than the version it declares. Versions that only added properties have no
+// function, only the version is updated.
+var migrations = []migration{
+	{"2.0.0", migrateTo200},
+	{"2.2.0", nil},
+	{"2.3.0", nil},
+	{"2.4.0", nil},
+	{"2.5.0", nil},
+	{"2.6.0", nil},
+	{"2.7.0", nil},
+	{"2.8.0", nil},
+	{"2.9.0", nil},
+	{patternsVersion, migrateTo2100},
+	{"2.11.0", nil},
+	{"2.12.0", nil},
+	{"2.13.0", nil},
+	{"2.14.0", nil},
+	{"2.15.0", nil},
+}
+
+// UpgradeManifest Migrate the content of a manifest, step-by-step, to the
+// current version. Returns the upgraded content and the versions it went
+// through, which is empty when the manifest is already current.
+func UpgradeManifest(content []byte) ([]byte, []string, error) {
+	version, e1 := manifestVersion(content)
+	if e1 != nil {
+		return nil, nil, e1
+	}
+
+	// Use the same rules as when reading a manifest to reject ones that are
+	// missing a version, or are newer than supported.
+	if _, e := selectDecoder(version); e != nil {
+		return nil, nil, e
+	}
+
+	current, _ := canonicalVersion(version)
+
+	var m map[string]interface{}
+	if e := decodeJson(content, &m); e != nil {
+		return nil, nil, fmt.Errorf(msg.Stderr.NewManifest, e.Error())
+	}
+
+	var steps []string
+	for _, mg := range migrations {
+		if semver.Compare(current, "v"+mg.to) >= 0 {
+			continue
+		}
+
+		log.Infof(msg.Stdout.MigratingManifest, m["version"], mg.to)
+
+		if mg.migrate != nil {
+			if e := mg.migrate(m); e != nil {
+				return nil, nil, fmt.Errorf(msg.Stderr.MigrateManifest, mg.to, e.Error())
+			}
+		}
+
+		m["version"] = mg.to
+		steps = append(steps, mg.to)
+	}
+
+	if len(steps) == 0 {
+		return content, nil, nil
+	}
+
+	if _, ok := m["$schema"]; ok {
+		m["$schema"] = SchemaUrl
+	}
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if e := enc.Encode(m); e != nil {
+		return nil, nil, fmt.Errorf(msg.Stderr.MigrateManifest, SchemaVersion, e.Error())
+	}
+
+	return buf.Bytes(), steps, nil
+}
+
+// migrateTo200 Rename properties from before version 2.0.0, mirroring the
+// compatibility decoder decodeManifestV1.
+func migrateTo200(m map[string]interface{}) error {
+	if excludes, ok := m["excludes"]; ok {
+		if _, found := m["copyAsIs"]; found {
+			return fmt.Errorf(msg.Stderr.MigrateConflict, "excludes", "copyAsIs")
+		}
+		m["copyAsIs"] = excludes
+		delete(m, "excludes")
+	}
+
+	if replace, ok := m["replace"]; ok {
+		r, _ := replace.(map[string]interface{})
+		if dir, found := r["directory"]; found {
+			m["substitute"] = dir
+		}
+		if files, found := r["files"]; found {
+			log.Warnf(msg.Stdout.ReplaceFilesIgnored, files)
+		}
+		delete(m, "replace")
+	}
+
+	validation, _ := m["validation"].([]interface{})
+	for _, v := range validation {
+		rule, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if pattern, found := rule["pattern"]; found {
+			rule["expression"] = pattern
+			delete(rule, "pattern")
+		}
+	}
+
+	return nil
+}
+
+// migrateTo2100 Warn about the patterns that may match other files, now that
+// they have the semantics of a .gitignore file. They are kept as they are,
+// only the template designer knows which files they were meant to match.
+func migrateTo2100(m map[string]interface{}) error {
+	content, e1 := json.Marshal(m)
+	if e1 != nil {
+		return e1
+	}
+
+	tm, e2 := decodeManifest(content)
+	if e2 != nil {
+		return e2
+	}
+
+	// On stderr, so it is not mixed in with an upgraded manifest printed to
+	// stdout.
+	warnPatternChanges(os.Stderr, tm)
+
+	return nil
+}
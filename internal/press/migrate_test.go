@@ -0,0 +1,74 @@
+package press
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpgradeManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantSteps []string
+		want      map[string]interface{}
+		wantErr   bool
+	}{
+		{
+			"from-1.1",
+			`{
+				"$schema": "https://raw.githubusercontent.com/kohirens/tmplpress/2.0.1/template.schema.json",
+				"version": "1.1",
+				"excludes": ["*.png"],
+				"replace": {"directory": "replace", "files": ["a:b"]},
+				"validation": [{"rule": "regExp", "fields": ["a"], "pattern": "^a$"}]
+			}`,
+			[]string{"2.0.0", "2.2.0", "2.3.0", "2.4.0", "2.5.0", "2.6.0", "2.7.0", "2.8.0", "2.9.0", "2.10.0", "2.11.0", "2.12.0", "2.13.0", "2.14.0", "2.15.0"},
+			map[string]interface{}{
+				"$schema":    SchemaUrl,
+				"version":    "2.15.0",
+				"copyAsIs":   []interface{}{"*.png"},
+				"substitute": "replace",
+				"validation": []interface{}{
+					map[string]interface{}{"rule": "regExp", "fields": []interface{}{"a"}, "expression": "^a$"},
+				},
+			},
+			false,
+		},
+		{
+			"from-2.1.0",
+			`{"version": "2.1.0", "skip": ["*.md"]}`,
+			[]string{"2.2.0", "2.3.0", "2.4.0", "2.5.0", "2.6.0", "2.7.0", "2.8.0", "2.9.0", "2.10.0", "2.11.0", "2.12.0", "2.13.0", "2.14.0", "2.15.0"},
+			map[string]interface{}{"version": "2.15.0", "skip": []interface{}{"*.md"}},
+			false,
+		},
+		{"current", `{"version": "2.15.0"}`, nil, map[string]interface{}{"version": "2.15.0"}, false},
+		{"conflict", `{"version": "1.2", "excludes": [], "copyAsIs": []}`, nil, nil, true},
+		{"missing-version", `{}`, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, steps, err := UpgradeManifest([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpgradeManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(steps, tt.wantSteps) {
+				t.Errorf("UpgradeManifest() steps = %v, want %v", steps, tt.wantSteps)
+			}
+
+			var m map[string]interface{}
+			if e := decodeJson(got, &m); e != nil {
+				t.Fatal(e)
+			}
+
+			if !reflect.DeepEqual(m, tt.want) {
+				t.Errorf("UpgradeManifest() got %v, want %v", m, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package press
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// gitExecutable The mode git records in its index for an executable file.
+const gitExecutable = "100755"
+
+// Mode A file mode for the output of the files that match the patterns, such
+// as "0755" for scripts.
+type Mode struct {
+	Files []string `json:"files"`
+	Mode  string   `json:"mode"`
+}
+
+// ModeFor The mode the manifest sets for a file, from the first entry with
+// patterns that match it, when there is one.
+func (tm *TmplManifest) ModeFor(relativePath string) (os.FileMode, bool) {
+	for _, m := range tm.Modes {
+		if InSkipArray(relativePath, m.Files) {
+			return parseFileMode(m.Mode)
+		}
+	}
+
+	return 0, false
+}
+
+// checkModes Verify each mode is an octal file mode.
+func checkModes(modes []*Mode) error {
+	for _, m := range modes {
+		if _, ok := parseFileMode(m.Mode); !ok {
+			return fmt.Errorf(msg.Stderr.Mode, m.Mode)
+		}
+
+		if e := checkFilePatterns(m.Files); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// gitExecutables The files of the template that git records as executable,
+// by their path relative to the template. Checkouts, such as on Windows or
+// with core.fileMode off, do not always make those files executable. It is
+// empty when the template is not in a git repository.
+func gitExecutables(tplDir string) map[string]bool {
+	executables := make(map[string]bool)
+
+	out, e := exec.Command("git", "-C", tplDir, "ls-files", "--stage", "-z").Output()
+	if e != nil {
+		return executables
+	}
+
+	// Each entry is "<mode> <object> <stage>\t<path>", with paths relative
+	// to the directory.
+	for _, entry := range bytes.Split(out, []byte{0}) {
+		info, path, found := strings.Cut(string(entry), "\t")
+		if found && strings.HasPrefix(info, gitExecutable+" ") {
+			executables[filepath.FromSlash(path)] = true
+		}
+	}
+
+	return executables
+}
+
+// parseFileMode Parse an octal file mode, such as "0755".
+func parseFileMode(text string) (os.FileMode, bool) {
+	mode, e := strconv.ParseUint(text, 8, 32)
+	if e != nil || mode > 0777 {
+		return 0, false
+	}
+
+	return os.FileMode(mode), true
+}
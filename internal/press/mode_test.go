@@ -0,0 +1,134 @@
+package press
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestTmplManifest_ModeFor(t *testing.T) {
+	tm := &TmplManifest{Modes: []*Mode{
+		{Files: []string{"bin/*"}, Mode: "0755"},
+		{Files: []string{"*.sh", "bin/private"}, Mode: "0700"},
+	}}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantMode os.FileMode
+		wantOk   bool
+	}{
+		{"first-match", "bin/run", 0755, true},
+		{"second-entry", "scripts/build.sh", 0700, true},
+		{"first-entry-wins", "bin/private", 0755, true},
+		{"no-match", "README.md", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMode, gotOk := tm.ModeFor(tt.path)
+			if gotMode != tt.wantMode || gotOk != tt.wantOk {
+				t.Errorf("ModeFor() = %v, %v, want %v, %v", gotMode, gotOk, tt.wantMode, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_checkModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		modes   []*Mode
+		wantErr bool
+	}{
+		{"valid", []*Mode{{Files: []string{"*.sh"}, Mode: "0755"}}, false},
+		{"no-leading-zero", []*Mode{{Files: []string{"*.sh"}, Mode: "644"}}, false},
+		{"not-octal", []*Mode{{Files: []string{"*.sh"}, Mode: "0800"}}, true},
+		{"too-big", []*Mode{{Files: []string{"*.sh"}, Mode: "01777"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkModes(tt.modes); (err != nil) != tt.wantErr {
+				t.Errorf("checkModes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrintModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not kept on Windows")
+	}
+
+	fixture := tmpDir + PS + "modes-01"
+	outDir := tmpDir + PS + "modes-01-out"
+	files := map[string]os.FileMode{
+		"build.sh":   0644, // executable in the git index only.
+		"logo.png":   0644,
+		"README.md":  0644,
+		"run.sh":     0755,
+		"secret.txt": 0644,
+	}
+
+	if e := os.MkdirAll(fixture, dirMode); e != nil {
+		t.Fatal(e)
+	}
+	for name, mode := range files {
+		if e := os.WriteFile(fixture+PS+name, []byte("{{.name}}\n"), mode); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.Chmod(fixture+PS+name, mode); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"update-index", "--chmod=+x", "build.sh"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = fixture
+		if out, e := cmd.CombinedOutput(); e != nil {
+			t.Fatalf("git %v: %v, %s", args, e, out)
+		}
+	}
+
+	tm := &TmplManifest{
+		CopyAsIs: []string{"*.png"},
+		Modes: []*Mode{
+			{Files: []string{"secret.txt", "logo.png"}, Mode: "0600"},
+		},
+		Version: SchemaVersion,
+	}
+
+	if e := Print(fixture, outDir, map[string]string{"name": "a"}, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want os.FileMode
+	}{
+		{"git-index", "build.sh", 0755},
+		{"manifest-copy", "logo.png", 0600},
+		{"file", "README.md", 0644},
+		{"executable-file", "run.sh", 0755},
+		{"manifest", "secret.txt", 0600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, e := os.Stat(outDir + PS + tt.file)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if got := info.Mode().Perm(); got != tt.want {
+				t.Errorf("mode of %v = %v, want %v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
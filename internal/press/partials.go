@@ -0,0 +1,79 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/stdlib/fsio"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// LoadPartials Parse the files in the partials directory of a template, so
+// the templates they define can be used in every file. Each partial is named
+// by its path relative to the template, and parsed with the delimiters for
+// that path. Returns nil when the template has no partials.
+func LoadPartials(tplDir string, tm *TmplManifest) (*template.Template, error) {
+	if tm.Partials == "" {
+		return nil, nil
+	}
+
+	dir, e := inTemplate(tplDir, "partials", tm.Partials)
+	if e != nil {
+		return nil, e
+	}
+
+	if !fsio.Exist(dir) {
+		return nil, fmt.Errorf(msg.Stderr.NoPartialsDir, dir)
+	}
+
+	files, e1 := FindTemplates(dir)
+	if e1 != nil {
+		return nil, e1
+	}
+
+	set := template.New("").Funcs(FuncMap)
+
+	for _, file := range files {
+		relativePath, _ := filepath.Rel(tplDir, file)
+
+		content, e2 := os.ReadFile(file)
+		if e2 != nil {
+			return nil, fmt.Errorf(msg.Stderr.CannotReadFile, file, e2.Error())
+		}
+
+		if _, e := set.New(relativePath).Delims(tm.DelimsFor(relativePath)).Parse(string(content)); e != nil {
+			return nil, fmt.Errorf(msg.Stderr.ParsePartial, relativePath, e.Error())
+		}
+	}
+
+	return set, nil
+}
+
+// NewTemplate Make a template to parse a file into, with any templates the
+// partials define available to it.
+func NewTemplate(name string, partials *template.Template) (*template.Template, error) {
+	if partials == nil {
+		return template.New(name).Funcs(FuncMap), nil
+	}
+
+	set, e := partials.Clone()
+	if e != nil {
+		return nil, e
+	}
+
+	return set.New(name), nil
+}
+
+// InPartials Indicates a file, relative to the root of the template, is in
+// the partials directory.
+func (tm *TmplManifest) InPartials(relativePath string) bool {
+	if tm.Partials == "" {
+		return false
+	}
+
+	dir := strings.Trim(filepath.ToSlash(filepath.Clean(tm.Partials)), "/") + "/"
+
+	return strings.HasPrefix(filepath.ToSlash(relativePath), dir)
+}
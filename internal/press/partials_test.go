@@ -0,0 +1,77 @@
+package press
+
+import (
+	"github.com/kohirens/stdlib/fsio"
+	"os"
+	"testing"
+)
+
+func TestPrintPartials(t *testing.T) {
+	fixture := fixtureDir + PS + "partials-01"
+	outDir := tmpDir + PS + "partials-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	vars := map[string]string{"appName": "billing", "owner": "Acme"}
+	if e := Print(fixture, outDir, vars, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"main.go", "// Copyright Acme\npackage billing\n"},
+		{"ci.yml", "steps:\n- run: go test ./...\n"},
+	}
+
+	for _, tt := range tests {
+		got, _ := os.ReadFile(outDir + PS + tt.file)
+		if string(got) != tt.want {
+			t.Errorf("%v got %q, want %q", tt.file, got, tt.want)
+		}
+	}
+
+	if fsio.Exist(outDir + PS + "_partials") {
+		t.Errorf("the partials directory was output")
+	}
+}
+
+func TestLoadPartialsMissing(t *testing.T) {
+	tm := &TmplManifest{Partials: "does-not-exist"}
+
+	if _, e := LoadPartials(fixtureDir+PS+"partials-01", tm); e == nil {
+		t.Errorf("LoadPartials() want an error")
+	}
+}
+
+func TestLoadPartialsOutside(t *testing.T) {
+	tm := &TmplManifest{Partials: "../partials-01/_partials"}
+
+	if _, e := LoadPartials(fixtureDir+PS+"data-01", tm); e == nil {
+		t.Errorf("LoadPartials() want an error for a partials directory outside of the template")
+	}
+}
+
+func TestInPartials(t *testing.T) {
+	tm := &TmplManifest{Partials: "./_partials/"}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"_partials/license.tmpl", true},
+		{"_partials/ci/steps.tmpl", true},
+		{"_partials.md", false},
+		{"src/_partials/license.tmpl", false},
+	}
+
+	for _, tt := range tests {
+		if got := tm.InPartials(tt.path); got != tt.want {
+			t.Errorf("InPartials(%q) got %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package press
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"golang.org/x/mod/semver"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// IgnoreFile Optional file in the root of a template, with more patterns
+	// of files to skip, one per line, as in a .gitignore file.
+	IgnoreFile = ".tmplpressignore"
+	// patternsVersion The version of the manifest from which patterns have
+	// the semantics of a .gitignore file.
+	patternsVersion = "2.10.0"
+)
+
+// pattern A pattern of files with the semantics of a .gitignore file.
+type pattern struct {
+	dirOnly bool
+	negate  bool
+	re      *regexp.Regexp
+	text    string
+}
+
+// patterns Compiled patterns, since the same few are matched against every
+// file of a template.
+var patterns sync.Map
+
+// Patterns A list of patterns with the semantics of a .gitignore file:
+//
+//   - "*" matches anything but a "/", "?" any one character but a "/", and
+//     "[a-z]" any one character in the range.
+//   - "**/" matches any number of directories, and a trailing "/**"
+//     everything in a directory.
+//   - A pattern with a "/" at the start or in the middle is from the root of
+//     the template, otherwise it matches the name of a file or directory at
+//     any depth.
+//   - A pattern that ends with a "/" only matches directories.
+//   - A pattern that starts with a "!" excludes what the patterns before it
+//     matched. The last pattern that matches a path wins.
+//   - A "\" escapes the character after it, such as "\!" or "\#".
+//
+// Everything in a directory that is matched is matched too.
+type Patterns []*pattern
+
+// NewPatterns Compile patterns. Blank patterns, and comments that start with
+// a "#", are ignored.
+func NewPatterns(texts []string) Patterns {
+	ps := make(Patterns, 0, len(texts))
+
+	for _, text := range texts {
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if p, ok := patterns.Load(text); ok {
+			ps = append(ps, p.(*pattern))
+			continue
+		}
+
+		p := compilePattern(text)
+		patterns.Store(text, p)
+		ps = append(ps, p)
+	}
+
+	return ps
+}
+
+// LiteralPattern A pattern that only matches the file at the path, relative
+// to the root of the template. Wildcards are escaped, and a file in the root
+// gets a "/" at the start, so it does not match files of the same name in
+// other directories.
+func LiteralPattern(relativePath string) string {
+	path := filepath.ToSlash(relativePath)
+	text := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(path)
+
+	if strings.HasPrefix(text, "!") || strings.HasPrefix(text, "#") {
+		text = `\` + text
+	}
+
+	if !strings.Contains(path, "/") {
+		text = "/" + text
+	}
+
+	return text
+}
+
+// ReadPatterns Read patterns from the content of a file like a .gitignore.
+// Trailing spaces are removed, unless escaped with a "\".
+func ReadPatterns(content []byte) []string {
+	var texts []string
+
+	lines := bufio.NewScanner(bytes.NewReader(content))
+	for lines.Scan() {
+		line := strings.TrimRight(lines.Text(), "\r")
+
+		for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+			line = line[:len(line)-1]
+		}
+
+		if line != "" && !strings.HasPrefix(line, "#") {
+			texts = append(texts, line)
+		}
+	}
+
+	return texts
+}
+
+// Match Indicates a path, relative to the root of the template, is matched,
+// and the pattern that matched it, or the directory it is in.
+func (ps Patterns) Match(relativePath string, isDir bool) (string, bool) {
+	path := strings.Trim(filepath.ToSlash(relativePath), "/")
+	parts := strings.Split(path, "/")
+
+	for i := 1; i < len(parts); i++ {
+		if text, ok := ps.match(strings.Join(parts[:i], "/"), true); ok {
+			return text, true
+		}
+	}
+
+	return ps.match(path, isDir)
+}
+
+// match The last pattern that matches the path, if it is not a negation.
+func (ps Patterns) match(path string, isDir bool) (string, bool) {
+	var found *pattern
+
+	for _, p := range ps {
+		if p.dirOnly && !isDir {
+			continue
+		}
+
+		if p.re.MatchString(path) {
+			found = p
+		}
+	}
+
+	if found == nil || found.negate {
+		return "", false
+	}
+
+	return found.text, true
+}
+
+// compilePattern Translate a pattern to a regular expression.
+func compilePattern(text string) *pattern {
+	p := &pattern{text: text}
+	s := text
+
+	if strings.HasPrefix(s, "!") {
+		p.negate = true
+		s = s[1:]
+	}
+
+	if strings.HasSuffix(s, "/") && !strings.HasSuffix(s, `\/`) {
+		p.dirOnly = true
+		s = strings.TrimRight(s, "/")
+	}
+
+	anchored := strings.Contains(s, "/")
+	s = strings.TrimPrefix(s, "/")
+
+	re := &strings.Builder{}
+	re.WriteString("^")
+	if !anchored {
+		re.WriteString("(?:.*/)?")
+	}
+
+	r := []rune(s)
+	for i := 0; i < len(r); i++ {
+		switch c := r[i]; {
+		case c == '*' && i+1 < len(r) && r[i+1] == '*' && (i == 0 || r[i-1] == '/') && (i+2 == len(r) || r[i+2] == '/'):
+			// A "**" that is a whole part of the path.
+			if i+2 == len(r) {
+				re.WriteString(".*")
+				i++
+			} else {
+				re.WriteString("(?:.*/)?")
+				i += 2
+			}
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '[':
+			end := classEnd(r, i)
+			if end < 0 {
+				re.WriteString(`\[`)
+				continue
+			}
+			class, negate := string(r[i+1:end]), ""
+			if strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^") {
+				class, negate = class[1:], "^/"
+			}
+			class = strings.NewReplacer(`\`, `\\`, "]", `\]`).Replace(class)
+			re.WriteString("[" + negate + class + "]")
+			i = end
+		case c == '\\' && i+1 < len(r):
+			i++
+			re.WriteString(regexp.QuoteMeta(string(r[i])))
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	re.WriteString("$")
+
+	compiled, e := regexp.Compile(re.String())
+	if e != nil {
+		// Match the pattern as it is, as when it has no wildcards.
+		compiled = regexp.MustCompile("^" + regexp.QuoteMeta(s) + "$")
+	}
+	p.re = compiled
+
+	return p
+}
+
+// classEnd The index of the "]" that closes the class at start, or -1. A "]"
+// right after the "[", or after a "!" or "^" there, is part of the class.
+func classEnd(r []rune, start int) int {
+	i := start + 1
+	if i < len(r) && (r[i] == '!' || r[i] == '^') {
+		i++
+	}
+	if i < len(r) && r[i] == ']' {
+		i++
+	}
+
+	for ; i < len(r); i++ {
+		if r[i] == ']' {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// PatternChange A pattern of a manifest that may match other files than
+// before, see PatternChanges.
+type PatternChange struct {
+	Pattern  string
+	Property string
+}
+
+func (pc PatternChange) String() string {
+	return pc.Property + ": " + pc.Pattern
+}
+
+// PatternChanges The patterns of a manifest that may match other files now
+// that they have the semantics of a .gitignore file, than they did as the
+// globs of manifests before version 2.10.0, where a "*" matched across a "/"
+// and a pattern was always the whole path. None for a manifest of 2.10.0 or
+// later.
+func PatternChanges(tm *TmplManifest) []PatternChange {
+	if v, ok := canonicalVersion(tm.Version); ok && semver.Compare(v, "v"+patternsVersion) >= 0 {
+		return nil
+	}
+
+	var changes []PatternChange
+
+	add := func(property string, texts []string) {
+		for _, text := range texts {
+			if patternMayChange(text) {
+				changes = append(changes, PatternChange{Pattern: text, Property: property})
+			}
+		}
+	}
+
+	add("skip", tm.Skip)
+	add("copyAsIs", tm.CopyAsIs)
+
+	for _, d := range tm.Delimiters {
+		add("delimiters", d.Files)
+	}
+
+	for _, fe := range tm.ForEach {
+		add("forEach", fe.Files)
+	}
+
+	return changes
+}
+
+// warnPatternChanges Warn of each pattern of the manifest that may match other
+// files than it did before version 2.10.0.
+func warnPatternChanges(w io.Writer, tm *TmplManifest) {
+	for _, change := range PatternChanges(tm) {
+		_, _ = fmt.Fprintf(w, msg.Stderr.PatternChanged+"\n", change)
+	}
+}
+
+// patternMayChange Indicates a glob may match other files as a pattern. Those
+// that match the same are a path with at most a "*" at the end, such as
+// "docs/*", and a name that starts with the only "*", such as "*.png".
+func patternMayChange(text string) bool {
+	if strings.ContainsAny(text, `?[\`) || strings.Contains(text, "**") ||
+		strings.HasPrefix(text, "!") || strings.HasPrefix(text, "/") ||
+		strings.HasPrefix(text, "#") || strings.HasSuffix(text, "/") {
+		return true
+	}
+
+	stars := strings.Count(text, "*")
+
+	if strings.Contains(text, "/") {
+		return stars > 1 || stars == 1 && !strings.HasSuffix(text, "*")
+	}
+
+	return stars != 1 || !strings.HasPrefix(text, "*")
+}
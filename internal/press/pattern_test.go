@@ -0,0 +1,137 @@
+package press
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"reflect"
+	"testing"
+)
+
+func TestPatterns_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{"name-any-depth", []string{"README.md"}, "docs/README.md", false, true},
+		{"anchored-root", []string{"/README.md"}, "docs/README.md", false, false},
+		{"anchored-middle", []string{"docs/*.md"}, "docs/a.md", false, true},
+		{"star-not-across-slash", []string{"docs/*.md"}, "docs/api/a.md", false, false},
+		{"double-star-prefix", []string{"**/fixtures"}, "a/b/fixtures/x.json", false, true},
+		{"double-star-middle", []string{"a/**/z.txt"}, "a/z.txt", false, true},
+		{"double-star-middle-deep", []string{"a/**/z.txt"}, "a/b/c/z.txt", false, true},
+		{"double-star-suffix", []string{"build/**"}, "build/out/app", false, true},
+		{"double-star-suffix-not-dir", []string{"build/**"}, "build", true, false},
+		{"in-matched-dir", []string{"node_modules"}, "node_modules/pkg/index.js", false, true},
+		{"dir-only-file", []string{"logs/"}, "logs", false, false},
+		{"dir-only-dir", []string{"logs/"}, "logs", true, true},
+		{"dir-only-in-dir", []string{"logs/"}, "app/logs/today.txt", false, true},
+		{"negation", []string{"*.png", "!logo.png"}, "img/logo.png", false, false},
+		{"negation-last-wins", []string{"!logo.png", "*.png"}, "img/logo.png", false, true},
+		{"negation-in-dir", []string{"assets/*", "!assets/keep.txt"}, "assets/keep.txt", false, false},
+		{"question-mark", []string{"file-?.txt"}, "file-1.txt", false, true},
+		{"question-mark-not-slash", []string{"a?b"}, "a/b", false, false},
+		{"class", []string{"file-[0-9].txt"}, "file-7.txt", false, true},
+		{"class-negated", []string{"file-[!0-9].txt"}, "file-7.txt", false, false},
+		{"escaped-negation", []string{`\!important.md`}, "!important.md", false, true},
+		{"unclosed-class", []string{"a[b"}, "a[b", false, true},
+		{"comment", []string{"# README.md"}, "README.md", false, false},
+		{"unicode", []string{"résumé-*.md"}, "résumé-2024.md", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := NewPatterns(tt.patterns).Match(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Match(%q) with %q = %v, want %v", tt.path, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPatterns(t *testing.T) {
+	content := "# comment\r\n*.log  \r\n\r\nkeep\\ \n!important.log\n"
+	want := []string{"*.log", `keep\ `, "!important.log"}
+
+	if got := ReadPatterns([]byte(content)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadPatterns() = %q, want %q", got, want)
+	}
+}
+
+func TestPatternChanges(t *testing.T) {
+	tm := &TmplManifest{
+		CopyAsIs: []string{"*.png", "assets/*", "docs/*.md"},
+		ForEach:  []*ForEach{{Files: []string{"services/**"}}},
+		Skip:     []string{"README.md", "*skip-me.md", "dir-to-skip/*", "a/b.txt", "!keep"},
+		Version:  "2.9.0",
+	}
+
+	want := []PatternChange{
+		{"README.md", "skip"},
+		{"!keep", "skip"},
+		{"docs/*.md", "copyAsIs"},
+		{"services/**", "forEach"},
+	}
+
+	if got := PatternChanges(tm); !reflect.DeepEqual(got, want) {
+		t.Errorf("PatternChanges() = %q, want %q", got, want)
+	}
+
+	tm.Version = patternsVersion
+	if got := PatternChanges(tm); got != nil {
+		t.Errorf("PatternChanges() = %q, want none for version %v", got, patternsVersion)
+	}
+}
+
+func Test_warnPatternChanges(t *testing.T) {
+	tm := &TmplManifest{Skip: []string{"docs/*.md"}, Version: "2.9.0"}
+
+	buf := &bytes.Buffer{}
+	warnPatternChanges(buf, tm)
+
+	want := fmt.Sprintf(msg.Stderr.PatternChanged+"\n", "skip: docs/*.md")
+	if buf.String() != want {
+		t.Errorf("warnPatternChanges() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	tm.Version = SchemaVersion
+	warnPatternChanges(buf, tm)
+
+	if buf.Len() != 0 {
+		t.Errorf("warnPatternChanges() = %q, want no warning for version %v", buf.String(), SchemaVersion)
+	}
+}
+
+func TestLiteralPattern(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		want  string
+		other string
+	}{
+		{"root", "logo.png", "/logo.png", "img/logo.png"},
+		{"nested", "img/logo.png", "img/logo.png", "logo.png"},
+		{"wildcards", "img/[a]*.png", `img/\[a]\*.png`, "img/a-b.png"},
+		{"negation", "!important", `/\!important`, "important"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LiteralPattern(tt.path)
+			if got != tt.want {
+				t.Errorf("LiteralPattern() = %q, want %q", got, tt.want)
+			}
+
+			if !InSkipArray(tt.path, []string{got}) {
+				t.Errorf("%q does not match %q", got, tt.path)
+			}
+
+			if InSkipArray(tt.other, []string{got}) {
+				t.Errorf("%q matches %q", got, tt.other)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package press
+
+import (
+	"context"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Kinds of output operation.
+const (
+	// opCopy Copy the file as-is.
+	opCopy = iota
+	// opDir Make the directory only.
+	opDir
+	// opLink Make a symbolic link to the target.
+	opLink
+	// opPress Parse the file as a template and execute it.
+	opPress
+	// opWrite Write the body of the file, without parsing it.
+	opWrite
+)
+
+// pressOp An output of a template, planned before any is made, so they can
+// be made in parallel.
+type pressOp struct {
+	data         interface{}
+	dstFile      string
+	kind         int
+	left         string
+	mode         os.FileMode
+	relativePath string
+	right        string
+	setMode      bool // set the mode, even on a file that was already there.
+	sourcePath   string
+	target       string // of a link.
+}
+
+// Jobs The number of files to press at the same time, the number of CPUs
+// when it is less than 1.
+func Jobs(n int) int {
+	if n < 1 {
+		return runtime.NumCPU()
+	}
+
+	return n
+}
+
+// run Make the planned output with a pool of workers. Operations are handed
+// out in the order they were planned, and none are started after one fails
+// or pressing is stopped. Returns the template file of the first operation,
+// in that order, that failed, with its error, so the same failure is
+// reported no matter which of the workers finished first.
+func (p *printer) run(stop context.Context, jobs int) (string, error) {
+	errs := make([]error, len(p.ops))
+	next := make(chan int)
+	failed := atomic.Bool{}
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < min(Jobs(jobs), len(p.ops)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if errs[i] = p.make(p.ops[i]); errs[i] != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	for i := range p.ops {
+		if failed.Load() || stop.Err() != nil {
+			break
+		}
+		next <- i
+	}
+
+	close(next)
+	wg.Wait()
+
+	for i, e := range errs {
+		if e != nil {
+			return p.ops[i].relativePath, e
+		}
+	}
+
+	if stop.Err() != nil {
+		return "", fmt.Errorf(msg.Stderr.Interrupted)
+	}
+
+	return "", nil
+}
+
+// make the output of an operation. A template file is read and rendered here,
+// in the job, rather than when it is planned.
+func (p *printer) make(op *pressOp) error {
+	if op.kind == opDir {
+		return os.MkdirAll(op.dstFile, dirMode)
+	}
+
+	if e := os.MkdirAll(filepath.Dir(op.dstFile), dirMode); e != nil {
+		return e
+	}
+
+	if op.kind == opLink {
+		return os.Symlink(op.target, op.dstFile)
+	}
+
+	if op.kind == opCopy {
+		if _, e := copyFile(op.sourcePath, op.dstFile); e != nil {
+			return e
+		}
+	} else {
+		_, text, codec, e1 := p.readTemplate(op.sourcePath, op.relativePath)
+		if e1 != nil {
+			return e1
+		}
+
+		if op.kind == opPress {
+			out, e := execute(op.sourcePath, text, op.data, op.left, op.right, p.partials)
+			if e != nil {
+				return e
+			}
+			text = out
+		}
+
+		out, e2 := codec.encode(op.relativePath, text)
+		if e2 != nil {
+			return e2
+		}
+
+		if e := os.WriteFile(op.dstFile, out, op.mode); e != nil {
+			return e
+		}
+	}
+
+	// The mode is only applied when a file is made, and is masked, so set
+	// it when it is not that of the template file.
+	if op.setMode {
+		return os.Chmod(op.dstFile, op.mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/stdlib/fsio"
+	"github.com/kohirens/stdlib/git"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPrintJobs(t *testing.T) {
+	fixture := fixtureDir + PS + "jobs-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	for _, jobs := range []int{0, 1, 2, 8} {
+		for i := 0; i < 5; i++ {
+			outDir := fmt.Sprintf("%v%vjobs-%v-%v", tmpDir, PS, jobs, i)
+
+			e := Print(fixture, outDir, map[string]string{"name": "press"}, tm, &PrintOptions{Jobs: jobs})
+
+			// Files 05 and 15 both fail, the first in the order of the walk
+			// has to be the one reported, whichever worker fails first.
+			if e == nil || !strings.Contains(e.Error(), "pressing 05.txt failed") {
+				t.Fatalf("Print() with %v jobs error = %v, want the first file that failed", jobs, e)
+			}
+
+			if fsio.Exist(outDir) {
+				t.Fatalf("Print() with %v jobs left the output directory", jobs)
+			}
+		}
+	}
+}
+
+func TestJobs(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want int
+	}{
+		{"given", 3, 3},
+		{"one", 1, 1},
+		{"zero", 0, Jobs(-1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Jobs(tt.n); got != tt.want || got < 1 {
+				t.Errorf("Jobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkPrint Press the templates of the test bundles with one worker, four
+// workers, and one for each CPU, which jobs 0 means.
+func BenchmarkPrint(b *testing.B) {
+	for _, bundle := range []string{"dir-to-dir-03", "repo-10", "repo-11", "repo-13"} {
+		tmplPath := tmpDir + PS + bundle
+		if !fsio.Exist(tmplPath) {
+			tmplPath = git.CloneFromBundle(bundle, tmpDir, fixtureDir, PS)
+		}
+
+		tm := &TmplManifest{}
+		if fsio.Exist(tmplPath + PS + TmplManifestFile) {
+			var e error
+			if tm, e = ReadTemplateJson(tmplPath + PS + TmplManifestFile); e != nil {
+				b.Fatal(e)
+			}
+		}
+
+		for _, jobs := range []int{1, 4, 0} {
+			b.Run(fmt.Sprintf("%v/jobs-%v", bundle, jobs), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					outDir := fmt.Sprintf("%v%vbench%v%v-%v-%v", tmpDir, PS, PS, bundle, jobs, i)
+
+					if e := Print(tmplPath, outDir, map[string]string{}, tm, &PrintOptions{Jobs: jobs}); e != nil {
+						b.Fatal(e)
+					}
+
+					b.StopTimer()
+					_ = os.RemoveAll(outDir)
+					b.StartTimer()
+				}
+			})
+		}
+	}
+}
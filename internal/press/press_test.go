@@ -1,7 +1,8 @@
 package press
 
 import (
-	"github.com/kohirens/stdlib/test"
+	stdt "github.com/kohirens/stdlib/test"
+	"github.com/kohirens/tmplpress/internal/test"
 	"os"
 	"testing"
 )
@@ -10,7 +11,8 @@ const tmpDir = "tmp"
 const fixtureDir = "testdata"
 
 func TestMain(m *testing.M) {
-	test.ResetDir(test.TmpDir, dirMode)
+	stdt.ResetDir(stdt.TmpDir, dirMode)
+	UseSchemas(test.Schemas())
 	// Run all tests
 	exitCode := m.Run()
 	// Clean up
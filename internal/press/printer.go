@@ -5,24 +5,29 @@ package press
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"fmt"
 	"github.com/kohirens/stdlib/fsio"
 	"github.com/kohirens/stdlib/log"
 	"github.com/kohirens/tmplpress/internal/msg"
-	"github.com/ryanuber/go-glob"
 	"golang.org/x/text/cases"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 )
 
 const (
 	dirMode      = 0744
 	gitConfigDir = ".git"
-	maxTmplSize  = 1e+7
 	PS           = string(os.PathSeparator)
+	// DefaultMaxRenderSize The largest file, in bytes, that is rendered
+	// when neither the manifest nor the user configuration set one.
+	DefaultMaxRenderSize = 1e+7
 )
 
 var FuncMap = template.FuncMap{
@@ -65,9 +70,12 @@ func FindTemplates(dir string) ([]string, error) {
 }
 
 // GetPlaceholderInput Checks for any missing placeholder values waits for their input from the CLI.
-func GetPlaceholderInput(placeholders *TmplManifest, tmplValues map[string]string, r *os.File, defaultVal string) error {
+// A placeholder with a default in the manifest is offered it, which is taken
+// when the input is empty. The output directory is used for a default from
+// its name. Give the same answers to Print, as the scanner may have read
+// ahead of the answers for the placeholders.
+func GetPlaceholderInput(placeholders *TmplManifest, tmplValues map[string]string, nPut *bufio.Scanner, defaultVal, outDir string) error {
 	tVals := tmplValues
-	nPut := bufio.NewScanner(r)
 
 	for placeholder, desc := range placeholders.Placeholders {
 		a, answered := tVals[placeholder]
@@ -78,17 +86,32 @@ func GetPlaceholderInput(placeholders *TmplManifest, tmplValues map[string]strin
 			continue
 		}
 
+		suggested := ""
+		if source, ok := placeholders.Defaults[placeholder]; ok {
+			suggested = ResolveDefault(source, outDir)
+		}
+
 		// Just use the default value for all un-set placeholders.
 		if defaultVal != " " {
 			tVals[placeholder] = defaultVal
+			if suggested != "" {
+				tVals[placeholder] = suggested
+			}
 			log.Infof(msg.Stdout.VarDefaultValue, placeholder)
 			continue
 		}
 
 		// Ask client for input.
-		fmt.Printf("\n%v - %v: ", placeholder, desc)
+		if suggested != "" {
+			fmt.Printf("\n%v - %v [%v]: ", placeholder, desc, suggested)
+		} else {
+			fmt.Printf("\n%v - %v: ", placeholder, desc)
+		}
 		nPut.Scan()
 		tVals[placeholder] = nPut.Text()
+		if tVals[placeholder] == "" {
+			tVals[placeholder] = suggested
+		}
 		log.Infof(msg.Stdout.Assignment, desc, tVals[placeholder])
 		log.Infof(msg.Stdout.Assignment, placeholder, tVals[placeholder])
 	}
@@ -96,8 +119,38 @@ func GetPlaceholderInput(placeholders *TmplManifest, tmplValues map[string]strin
 	return nil
 }
 
-// Print templates to the output directory.
-func Print(tplDir, outDir string, vars map[string]string, tmplJson *TmplManifest) error {
+// PrintOptions Settings for pressing a template, beyond its manifest.
+type PrintOptions struct {
+	// Conflicts The policies for the files the output directory already has.
+	// They are overwritten when there are none.
+	Conflicts []*ConflictRule
+
+	// Context Stops pressing when it is done, such as on Ctrl-C, when nil
+	// pressing cannot be stopped.
+	Context context.Context
+
+	// DataFiles given to every file along with those of the template.
+	DataFiles []string
+
+	// Answers Where the answers are read from, for the conflict policy to
+	// prompt, when nil it is os.Stdin. Share it with GetPlaceholderInput, so
+	// the answers it has read ahead are not lost.
+	Answers *bufio.Scanner
+
+	// Jobs The size of the pool of workers that press the files, see Jobs.
+	Jobs int
+
+	// MaxRenderSize The largest file, in bytes, that is rendered, from the
+	// user configuration, see RenderLimit.
+	MaxRenderSize int64
+
+	// Meta The metadata given to every file, when nil it is made from the
+	// current time and the template directory.
+	Meta *Metadata
+}
+
+// Print templates to the output directory. The options may be nil.
+func Print(tplDir, outDir string, vars map[string]string, tmplJson *TmplManifest, opts *PrintOptions) error {
 	if !fsio.Exist(tplDir) {
 		return fmt.Errorf(msg.Stderr.PathNotExist, tplDir)
 	}
@@ -108,79 +161,162 @@ func Print(tplDir, outDir string, vars map[string]string, tmplJson *TmplManifest
 	log.Infof("template: %v", normTplDir)
 	log.Infof("output: %v", normOutDir)
 
-	// Recursively walk the template directory.
-	return filepath.Walk(normTplDir, func(sourcePath string, fi os.FileInfo, wErr error) error {
-		if wErr != nil {
-			return wErr
-		}
+	partials, e0 := LoadPartials(normTplDir, tmplJson)
+	if e0 != nil {
+		return e0
+	}
 
-		// Do not parse directories.
-		if fi.IsDir() {
-			return nil
-		}
+	if opts == nil {
+		opts = &PrintOptions{}
+	}
 
-		if strings.Contains(sourcePath, gitConfigDir+PS) {
-			return nil
-		}
+	// On stderr, so the press of a manifest from before version 2.10.0 says
+	// which of its patterns may now match other files.
+	warnPatternChanges(os.Stderr, tmplJson)
 
-		// Skip all files in the substitute directory.
-		if hasParentDir(tmplJson.Substitute, sourcePath) {
-			log.Infof("substitute skip %v", sourcePath)
-			return nil
-		}
+	data, e2 := LoadData(normTplDir, tmplJson, opts.DataFiles)
+	if e2 != nil {
+		return e2
+	}
+
+	meta := opts.Meta
+	if meta == nil {
+		meta = NewMetadata(time.Now(), "", &TemplateMeta{Source: tplDir}, nil)
+	}
+
+	ctx, e1 := templateData(vars, tmplJson.Types, data, meta)
+	if e1 != nil {
+		return e1
+	}
 
-		// Skip processing files if a template file is too big.
-		if fi.Size() > maxTmplSize {
-			return fmt.Errorf(msg.Stderr.FileTooBig, maxTmplSize)
+	stop := opts.Context
+	if stop == nil {
+		stop = context.Background()
+	}
+
+	// Press into a staging directory, which is only moved into place once
+	// every file is pressed, so a failure leaves nothing half-written.
+	staging, e3 := newStaging(normOutDir)
+	if e3 != nil {
+		return e3
+	}
+
+	p := &printer{
+		executables: gitExecutables(normTplDir),
+		outDir:      staging,
+		partials:    partials,
+		printed:     make(map[string]string),
+		tm:          tmplJson,
+	}
+
+	current := ""
+	limit := RenderLimit(tmplJson, opts.MaxRenderSize)
+	w := &Walker{Tm: tmplJson}
+
+	e4 := w.Walk(normTplDir, func(sourcePath, relativePath string, d fs.DirEntry) error {
+		current = relativePath
+
+		if stop.Err() != nil {
+			return fmt.Errorf(msg.Stderr.Interrupted)
 		}
 
 		log.Infof(msg.Stdout.Processing, sourcePath)
+		log.Infof(msg.Stdout.RelativeDir, relativePath)
 
-		currFile := filepath.Base(sourcePath)
+		// Placeholders in the names of files and directories are filled in too.
+		outPath, e0 := renderPath(tmplJson.OutputPath(relativePath), ctx)
+		if e0 != nil {
+			return e0
+		}
 
-		// Normalize the path separator before performing any operations.
-		normSourcePath := fsio.Normalize(sourcePath)
+		// A value such as "../x" must not put the file outside the output.
+		outPath, e0 = inOutDir(relativePath, outPath)
+		if e0 != nil {
+			return e0
+		}
 
-		// Get the relative path of the file from root of the template and
-		// append it to the output directory, so that files are placed in their
-		// correct subdirectories in the output.
-		relativePath := strings.TrimLeft(strings.ReplaceAll(normSourcePath, normTplDir, ""), "\\/")
-		log.Infof(msg.Stdout.RelativeDir, relativePath)
+		dstFile := filepath.Clean(staging + PS + outPath)
 
-		// Skip the template manifest file and the git config directory.
-		if currFile == TmplManifestFile {
-			log.Infof(msg.Stdout.Skipping, relativePath)
+		// For empty directories, make the directory and nothing else.
+		if d.Name() == tmplJson.EmptyDirFile {
+			saveDir := filepath.Dir(dstFile)
+			log.Infof(msg.Stdout.SaveDir, saveDir)
+			p.ops = append(p.ops, &pressOp{dstFile: saveDir, kind: opDir, relativePath: relativePath})
 			return nil
 		}
 
-		// Don't do anything with the files in this list.
-		if InSkipArray(relativePath, tmplJson.Skip) {
-			log.Infof(msg.Stdout.Skipping, sourcePath)
-			return nil
+		if tmplJson.PreservesLink(d) {
+			return p.planLink(sourcePath, relativePath, outPath, ctx)
 		}
 
-		saveDir := filepath.Clean(normOutDir + PS + filepath.Dir(relativePath))
-		log.Infof(msg.Stdout.SaveDir, saveDir)
+		if d.Type()&fs.ModeSymlink != 0 {
+			if e := checkFollow(normTplDir, sourcePath, relativePath); e != nil {
+				return e
+			}
+		}
 
-		// Make all subdirectories in output path.
-		if e := os.MkdirAll(saveDir, dirMode); e != nil {
-			return e
+		if matchCopyAsIs(tmplJson.CopyAsIs, relativePath) {
+			log.Infof(msg.Stdout.CopyAsIs, sourcePath)
+			return p.planCopy(sourcePath, relativePath, outPath)
 		}
 
-		// For empty directories, return here so that the directory is made and nothing else.
-		if currFile == tmplJson.EmptyDirFile {
-			return nil
+		// Copy binary files as-is too, unless the designer says they are
+		// templates, or in an encoding other than UTF-8.
+		if tmplJson.ChecksBinary(relativePath) {
+			binary, e := SniffBinary(sourcePath)
+			if e != nil {
+				return e
+			}
+
+			if binary {
+				log.Infof(msg.Stdout.Binary, sourcePath)
+				return p.planCopy(sourcePath, relativePath, outPath)
+			}
 		}
 
-		copied, e1 := copyAsIs(tmplJson.CopyAsIs, relativePath, sourcePath, saveDir)
-		if e1 != nil {
-			return e1
-		} else if copied {
-			return nil
+		// Only files that are rendered are read into memory, so only those
+		// are limited in size.
+		fi, e5 := os.Stat(sourcePath)
+		if e5 != nil {
+			return e5
+		}
+
+		if fi.Size() > limit {
+			return fmt.Errorf(msg.Stderr.FileTooBig, relativePath, fi.Size(), limit)
 		}
 
-		return parse(sourcePath, saveDir, vars)
+		if fe := tmplJson.ForEachFor(relativePath); fe != nil {
+			return p.planEach(fe, sourcePath, relativePath, vars[fe.List], ctx)
+		}
+
+		return p.planFile(sourcePath, relativePath, outPath, ctx)
 	})
+
+	// Only press the files once they are all planned, the walk stops at the
+	// first that cannot be.
+	if e4 == nil {
+		current, e4 = p.run(stop, opts.Jobs)
+	}
+
+	if e4 != nil {
+		return rollbackStaging(staging, normOutDir, current, e4)
+	}
+
+	answers := opts.Answers
+	if answers == nil {
+		answers = bufio.NewScanner(os.Stdin)
+	}
+
+	policies, e5 := resolveConflicts(staging, normOutDir, opts.Conflicts, answers)
+	if e5 != nil {
+		return rollbackStaging(staging, normOutDir, "", e5)
+	}
+
+	if e := commitStaging(staging, normOutDir, policies); e != nil {
+		return rollbackStaging(staging, normOutDir, "", e)
+	}
+
+	return nil
 }
 
 func ShowAllPlaceholderValues(tm *TmplManifest, tmplValues map[string]string) {
@@ -195,84 +331,304 @@ func ShowAllPlaceholderValues(tm *TmplManifest, tmplValues map[string]string) {
 	}
 }
 
-// copyAsIs Check a file matches a glob pattern, if so, then copy it to the
-// output as-is (without template parsing).
-func copyAsIs(files []string, relativePath, sourcePath, saveDir string) (bool, error) {
-	if len(files) < 1 { // no-op
-		return false, nil
+// RenderLimit The largest file, in bytes, that is rendered. That of the
+// manifest, as the designer knows the template best, else that of the user
+// configuration, else DefaultMaxRenderSize.
+func RenderLimit(tm *TmplManifest, configured int64) int64 {
+	if tm != nil && tm.MaxRenderSize > 0 {
+		return tm.MaxRenderSize
 	}
 
-	for _, exclude := range files {
-		// check if the file matches a pattern
-		if glob.Glob(exclude, relativePath) {
-			log.Infof(msg.Stdout.CopyAsIs, sourcePath)
-			_, e := copyToDir(sourcePath, saveDir, PS)
-			return true, e
-		}
+	if configured > 0 {
+		return configured
 	}
 
-	return false, nil
+	return DefaultMaxRenderSize
+}
+
+// matchCopyAsIs Indicates a file matches a pattern of the files to copy
+// as-is.
+func matchCopyAsIs(files []string, relativePath string) bool {
+	return InSkipArray(relativePath, files)
 }
 
-// copyToDir Copy a file to a directory.
-func copyToDir(sourcePath, destDir, separator string) (int64, error) {
+// copyFile Copy a file, streaming it, with the mode of the source.
+func copyFile(sourcePath, dstFile string) (int64, error) {
 	//TODO: Move to stdlib.
 	sFile, err1 := os.Open(sourcePath)
 	if err1 != nil {
 		return 0, err1
 	}
+	defer sFile.Close()
 
-	fileStats, err2 := os.Stat(sourcePath)
+	info, err2 := sFile.Stat()
 	if err2 != nil {
 		return 0, err2
 	}
 
-	dstFile := destDir + separator + fileStats.Name()
-	dFile, err3 := os.Create(dstFile)
+	dFile, err3 := os.OpenFile(dstFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
 	if err3 != nil {
 		return 0, err3
 	}
 
-	return io.Copy(dFile, sFile)
-}
+	n, err4 := io.Copy(dFile, sFile)
+	if err4 != nil {
+		_ = dFile.Close()
+		return n, err4
+	}
 
-// hasParentDir Detect if a strings is part of a path.
-func hasParentDir(parent, dir string) bool {
-	if parent != "" && strings.Contains(dir, parent) {
-		return true
+	// A write can fail to be flushed until the file is closed.
+	if e := dFile.Close(); e != nil {
+		return n, fmt.Errorf(msg.Stderr.CouldNotCloseFile, dstFile, e.Error())
 	}
-	return false
-}
 
-// parse a file as a Go template.
-func parse(tplFile, dstDir string, vars map[string]string) error {
-	log.Infof(msg.Stdout.Parsing, tplFile)
-	funcMap := FuncMap
+	return n, nil
+}
 
+// execute the content of a file as a Go template, with the delimiters given,
+// or the default when they are empty, returning the output. The templates
+// defined by the partials, if any, can be used in the file.
+func execute(tplFile string, content []byte, data interface{}, left, right string, partials *template.Template) ([]byte, error) {
 	tmplName := filepath.Base(tplFile)
-	parser, err1 := template.New(tmplName).Funcs(funcMap).ParseFiles(tplFile)
+	tmpl, err0 := NewTemplate(tmplName, partials)
+	if err0 != nil {
+		return nil, err0
+	}
+
+	parser, err1 := tmpl.Delims(left, right).Parse(string(content))
 	if err1 != nil {
-		return err1
+		return nil, err1
 	}
 
-	fileStats, err2 := os.Stat(tplFile)
-	if err2 != nil {
-		return err2
+	out := &bytes.Buffer{}
+	if e := parser.Execute(out, data); e != nil {
+		return nil, e
 	}
 
-	dstFile := dstDir + PS + fileStats.Name()
-	file, err3 := os.OpenFile(dstFile, os.O_CREATE|os.O_WRONLY, fileStats.Mode())
-	if err3 != nil {
-		return err3
+	return out.Bytes(), nil
+}
+
+// printer Plans the files of a template to press to the output directory.
+type printer struct {
+	executables map[string]bool // files git records as executable.
+	ops         []*pressOp
+	outDir      string
+	partials    *template.Template
+	printed     map[string]string // files output, to the template file of each.
+	tm          *TmplManifest
+}
+
+// modeFor The mode of the output of a file: that the manifest sets for it,
+// else that of the file, made executable when git records it so. Indicates
+// when it is not just the mode of the file, so it must be set.
+func (p *printer) modeFor(sourcePath, relativePath string) (os.FileMode, bool, error) {
+	if mode, ok := p.tm.ModeFor(relativePath); ok {
+		return mode, true, nil
 	}
 
-	if e := parser.Execute(file, vars); e != nil {
-		return e
+	info, e := os.Stat(sourcePath)
+	if e != nil {
+		return 0, false, e
+	}
+
+	mode := info.Mode().Perm()
+	if p.executables[relativePath] && mode&0111 == 0 {
+		return mode | 0111, true, nil
 	}
 
-	if e := file.Close(); e != nil {
+	return mode, false, nil
+}
+
+// planCopy Plan to copy a file as-is.
+func (p *printer) planCopy(sourcePath, relativePath, outPath string) error {
+	mode, setMode, e := p.modeFor(sourcePath, relativePath)
+	if e != nil {
 		return e
 	}
 
+	dstFile := filepath.Clean(p.outDir + PS + outPath)
+
+	if other, ok := p.printed[dstFile]; ok {
+		return fmt.Errorf(msg.Stderr.OutputCollision, other, relativePath, outPath)
+	}
+	p.printed[dstFile] = relativePath
+
+	p.ops = append(p.ops, &pressOp{dstFile: dstFile, kind: opCopy, mode: mode, relativePath: relativePath, setMode: setMode, sourcePath: sourcePath})
+
+	return nil
+}
+
+// planEach Plan a file once for each item of a list placeholder, to the path
+// made from the item.
+func (p *printer) planEach(fe *ForEach, sourcePath, relativePath, list string, ctx map[string]interface{}) error {
+	items, e1 := ListItems(list)
+	if e1 != nil {
+		return fmt.Errorf(msg.Stderr.ForEachList, fe.List, relativePath, e1.Error())
+	}
+
+	for _, item := range items {
+		data := itemData(ctx, fe.ItemName(), item)
+
+		outPath, e2 := renderPath(fe.Path, data)
+		if e2 != nil {
+			return e2
+		}
+
+		// An item such as "../x" must not put the file outside the output.
+		outPath, e2 = inOutDir(relativePath, outPath)
+		if e2 != nil {
+			return e2
+		}
+
+		if e := p.planFile(sourcePath, relativePath, outPath, data); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// planFile Plan to press a template file to the output directory, as its
+// front matter, if any, says. The data is the placeholders and data files,
+// along with the item for files pressed for each item of a list.
+func (p *printer) planFile(sourcePath, relativePath, outPath string, data interface{}) error {
+	// Only the front matter is kept, the file is read again when it is made,
+	// so the content of every file is not held at once.
+	fm, _, _, e1 := p.readTemplate(sourcePath, relativePath)
+	if e1 != nil {
+		return e1
+	}
+
+	include, e3 := fm.Include(data)
+	if e3 != nil {
+		return fmt.Errorf(msg.Stderr.FrontMatterFile, relativePath, e3.Error())
+	}
+
+	if !include {
+		log.Infof(msg.Stdout.FrontMatterExclude, relativePath)
+		return nil
+	}
+
+	fmPath, e4 := fm.OutPath(outPath, data)
+	if e4 != nil {
+		return fmt.Errorf(msg.Stderr.FrontMatterFile, relativePath, e4.Error())
+	}
+
+	mode, hasMode, e5 := fm.FileMode()
+	if e5 != nil {
+		return fmt.Errorf(msg.Stderr.FrontMatterFile, relativePath, e5.Error())
+	}
+
+	if !hasMode {
+		m, set, e := p.modeFor(sourcePath, relativePath)
+		if e != nil {
+			return e
+		}
+		mode, hasMode = m, set
+	}
+
+	dstFile := filepath.Clean(p.outDir + PS + fmPath)
+
+	// Two files, or two items of a list, made into the same path would
+	// quietly overwrite one another.
+	if other, ok := p.printed[dstFile]; ok {
+		return fmt.Errorf(msg.Stderr.OutputCollision, other, relativePath, fmPath)
+	}
+	p.printed[dstFile] = relativePath
+
+	log.Infof(msg.Stdout.SaveDir, filepath.Dir(dstFile))
+
+	op := &pressOp{
+		data:         data,
+		dstFile:      dstFile,
+		kind:         opPress,
+		mode:         mode,
+		relativePath: relativePath,
+		setMode:      hasMode,
+		sourcePath:   sourcePath,
+	}
+
+	if fm.Raw {
+		log.Infof(msg.Stdout.FrontMatterRaw, relativePath)
+		op.kind = opWrite
+	} else {
+		log.Infof(msg.Stdout.Parsing, sourcePath)
+		op.left, op.right = p.tm.FileDelims(relativePath, fm)
+	}
+
+	p.ops = append(p.ops, op)
+
 	return nil
 }
+
+// readTemplate Read a template file, in the text format the manifest gives
+// it, into its front matter, empty when it has none, and the body after it,
+// along with the codec to write the output with.
+func (p *printer) readTemplate(sourcePath, relativePath string) (*FrontMatter, []byte, *textCodec, error) {
+	content, e1 := os.ReadFile(sourcePath)
+	if e1 != nil {
+		return nil, nil, nil, fmt.Errorf(msg.Stderr.CannotReadFile, sourcePath, e1.Error())
+	}
+
+	text, codec, e2 := p.tm.readText(relativePath, content)
+	if e2 != nil {
+		return nil, nil, nil, e2
+	}
+
+	fm, body, e3 := ReadFrontMatter(text)
+	if e3 != nil {
+		return nil, nil, nil, fmt.Errorf(msg.Stderr.FrontMatterFile, relativePath, e3.Error())
+	}
+
+	if fm == nil {
+		fm = &FrontMatter{}
+	}
+
+	return fm, body, codec, nil
+}
+
+// inOutDir The output path of a template file, cleaned, when it is in the
+// output directory.
+func inOutDir(relativePath, outPath string) (string, error) {
+	p := filepath.Clean(filepath.FromSlash(outPath))
+	if escapesOutDir(p) {
+		return "", fmt.Errorf(msg.Stderr.OutPathOutside, relativePath, outPath)
+	}
+
+	return p, nil
+}
+
+// escapesOutDir Indicates a clean path, relative to the output directory, is
+// not in it, or is the output directory itself.
+func escapesOutDir(p string) bool {
+	return p == "." || p == ".." || filepath.IsAbs(p) || strings.HasPrefix(p, ".."+PS)
+}
+
+// renderPath Fill in the placeholders in the path of a file.
+func renderPath(relativePath string, data interface{}) (string, error) {
+	if !strings.Contains(relativePath, "{{") {
+		return relativePath, nil
+	}
+
+	res, e := renderText(relativePath, data)
+	if e != nil {
+		return "", fmt.Errorf(msg.Stderr.RenderPath, relativePath, e.Error())
+	}
+
+	return res, nil
+}
+
+// renderText Fill in the placeholders in a short template, such as a path.
+func renderText(text string, data interface{}) (string, error) {
+	parser, err1 := template.New(text).Funcs(FuncMap).Parse(text)
+	if err1 != nil {
+		return "", err1
+	}
+
+	sb := &strings.Builder{}
+	if e := parser.Execute(sb, data); e != nil {
+		return "", e
+	}
+
+	return sb.String(), nil
+}
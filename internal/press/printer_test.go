@@ -8,6 +8,8 @@ import (
 	test2 "github.com/kohirens/stdlib/test"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -71,7 +73,7 @@ func TestEmptyDirectoryFeature(runner *testing.T) {
 
 	for _, tc := range fixtures {
 		runner.Run(tc.name, func(t *testing.T) {
-			e1 := Print(tc.srcDir, tc.dstDir, tc.vars, &TmplManifest{EmptyDirFile: ".empty", CopyAsIs: []string{}})
+			e1 := Print(tc.srcDir, tc.dstDir, tc.vars, &TmplManifest{EmptyDirFile: ".empty", CopyAsIs: []string{}}, nil)
 
 			if e1 != nil {
 				t.Errorf("got error %v, want nil", e1.Error())
@@ -107,7 +109,7 @@ func TestPrinting(tester *testing.T) {
 
 	for _, tc := range tests {
 		tester.Run(tc.name, func(test *testing.T) {
-			err := Print(tc.tmplPath, tc.outPath, tc.tplVars, &TmplManifest{})
+			err := Print(tc.tmplPath, tc.outPath, tc.tplVars, &TmplManifest{}, nil)
 
 			if err != nil {
 				test.Errorf("got an error %q", err.Error())
@@ -164,7 +166,7 @@ func TestSkipping(tester *testing.T) {
 
 	tmplPath := git.CloneFromBundle(repoFixture, test2.TmpDir, test2.FixtureDir, PS)
 
-	err := Print(tmplPath, outPath, tc.answers, tc.ph)
+	err := Print(tmplPath, outPath, tc.answers, tc.ph, nil)
 
 	if err != nil {
 		tester.Errorf("got an error %q", err)
@@ -225,7 +227,7 @@ func TestSubstitute(tester *testing.T) {
 
 	tmplPath := git.CloneFromBundle(repoFixture, test2.TmpDir, test2.FixtureDir, PS)
 
-	err := Print(tmplPath, outPath, tc.answers, tc.ph)
+	err := Print(tmplPath, outPath, tc.answers, tc.ph, nil)
 
 	if err != nil {
 		tester.Errorf("got an error %q", err)
@@ -247,75 +249,237 @@ func TestSubstitute(tester *testing.T) {
 	}
 }
 
-func Test_hasParentDir(t *testing.T) {
+func Test_planCopy(t *testing.T) {
+	// Git bundle to use as the template.
+	repoFixture := "repo-13"
+	fixture := git.CloneFromBundle(repoFixture, tmpDir, fixtureDir, PS)
+	// Where to place the template output.
+	saveDir := tmpDir + PS + "processed" + PS + repoFixture
+
 	tests := []struct {
-		name   string
-		parent string
-		dir    string
-		want   bool
+		name     string
+		copyAsIs []string
+		file     string
+		want     bool
 	}{
-		{
-			"case-1",
-			"abc",
-			"c:\\abc\\123\\file1",
-			true,
-		},
-		{
-			"case-2",
-			"xyz",
-			"c:\\abc\\123\\file1",
-			false,
-		},
+		{"case-1", []string{"*.txt"}, ".auto/auto-test.txt", true},
+		{"case-2", []string{"*.txt"}, "file-1.tmpl", false},
+		{"case-3", []string{"*.gif", "*.txt"}, "test.txt", true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := hasParentDir(tt.parent, tt.dir)
+			p := &printer{outDir: saveDir, printed: map[string]string{}, tm: &TmplManifest{CopyAsIs: tt.copyAsIs}}
 
-			if got != tt.want {
-				t.Errorf("hasParentDir() = %v, want %v", got, tt.want)
+			if got := matchCopyAsIs(p.tm.CopyAsIs, tt.file); got != tt.want {
+				t.Fatalf("matchCopyAsIs() got = %v, want %v", got, tt.want)
+			}
+
+			if !tt.want {
+				return
+			}
+
+			if e := p.planCopy(fixture+PS+tt.file, tt.file, tt.file); e != nil {
+				t.Fatalf("planCopy() error = %v", e)
+			}
+
+			if len(p.ops) != 1 || p.ops[0].kind != opCopy {
+				t.Fatalf("planCopy() got ops %v, want a single copy", p.ops)
+			}
+
+			if e := p.make(p.ops[0]); e != nil {
+				t.Fatalf("make() error = %v", e)
+			}
+
+			want, _ := os.ReadFile(fixture + PS + tt.file)
+			got, e := os.ReadFile(saveDir + PS + tt.file)
+			if e != nil || !bytes.Equal(got, want) {
+				t.Errorf("make() did not copy %v as-is, got %q, want %q", tt.file, got, want)
+			}
+
+			if e := p.planCopy(fixture+PS+tt.file, "other", tt.file); e == nil {
+				t.Errorf("planCopy() want an error for a second file to the same output")
 			}
 		})
 	}
 }
 
-func Test_copyAsIs(t *testing.T) {
-	// Git bundle to use as the template.
-	repoFixture := "repo-13"
-	fixture := git.CloneFromBundle(repoFixture, tmpDir, fixtureDir, PS)
-	// Where to place the template output.
-	saveDir := tmpDir + PS + "processed" + PS + repoFixture
-	_ = os.MkdirAll(saveDir, dirMode)
+func Test_renderPath(t *testing.T) {
+	vars := map[string]string{"serviceName": "billing"}
 
 	tests := []struct {
 		name    string
-		ignores []string
-		file    string
-		saveDir string
-		want    bool
+		path    string
+		want    string
 		wantErr bool
 	}{
-		{"case-1", []string{"*.txt"}, ".auto/auto-test.txt", saveDir + PS + ".auto", true, false},
-		{"case-2", []string{"*.txt"}, "file-1.tmpl", "", false, false},
-		{"case-3", []string{"*.gif", "*.txt"}, "test.txt", saveDir, true, false},
+		{"no-placeholders", "cmd/main.go", "cmd/main.go", false},
+		{"directory", "cmd/{{.serviceName}}/main.go", "cmd/billing/main.go", false},
+		{"file", "{{.serviceName}}.go", "billing.go", false},
+		{"bad-action", "{{.serviceName.go", "", true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_ = os.MkdirAll(tt.saveDir, 0774)
+			got, err := renderPath(tt.path, vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
 
-			got, err := copyAsIs(tt.ignores, tt.file, fixture+PS+tt.file, tt.saveDir)
+			if got != tt.want {
+				t.Errorf("renderPath() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
 
-			if (err != nil) != tt.wantErr {
-				t.Errorf("copyAsIs() error = %v, wantErr %v", err, tt.wantErr)
+func TestPrintMaxRenderSize(t *testing.T) {
+	fixture := tmpDir + PS + "max-render-size-01"
+	files := map[string]string{
+		"big.png":   "\x89PNG\r\n\x1a\n" + strings.Repeat("x", 64),
+		"big.txt":   strings.Repeat("{{.name}}", 8),
+		"small.txt": "{{.name}}",
+	}
+	for name, content := range files {
+		if e := os.MkdirAll(fixture, dirMode); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(fixture+PS+name, []byte(content), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		manifest   int64
+		configured int64
+		wantErr    string
+	}{
+		{"default", 0, 0, ""},
+		{"manifest", 32, 0, "big.txt is too big to render, 72 bytes, the limit is 32 bytes"},
+		{"configured", 0, 32, "big.txt is too big to render, 72 bytes, the limit is 32 bytes"},
+		{"manifest-over-configured", 100, 32, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := tmpDir + PS + "max-render-size-" + tt.name
+			tm := &TmplManifest{MaxRenderSize: tt.manifest, Version: SchemaVersion}
+
+			err := Print(fixture, outDir, map[string]string{"name": "a"}, tm, &PrintOptions{MaxRenderSize: tt.configured})
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Print() error = %v", err)
+				}
+				if !fsio.Exist(outDir + PS + "big.png") {
+					t.Errorf("Print() did not copy big.png")
+				}
 				return
 			}
-			if got != tt.want {
-				t.Errorf("copyAsIs() got = %v, want %v", got, tt.want)
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Print() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		tm         *TmplManifest
+		configured int64
+		want       int64
+	}{
+		{"default", &TmplManifest{}, 0, DefaultMaxRenderSize},
+		{"no-manifest", nil, 0, DefaultMaxRenderSize},
+		{"configured", &TmplManifest{}, 20, 20},
+		{"manifest", &TmplManifest{MaxRenderSize: 30}, 20, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderLimit(tt.tm, tt.configured); got != tt.want {
+				t.Errorf("RenderLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_copyFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not kept on Windows")
+	}
+
+	src := tmpDir + PS + "copy-file-src.sh"
+	dst := tmpDir + PS + "copy-file-dst.sh"
+	content := strings.Repeat("echo tmplpress\n", 4096)
+
+	if e := os.WriteFile(src, []byte(content), 0755); e != nil {
+		t.Fatal(e)
+	}
+	if e := os.Chmod(src, 0755); e != nil {
+		t.Fatal(e)
+	}
+
+	n, err := copyFile(src, dst)
+	if err != nil {
+		t.Fatalf("copyFile() error = %v", err)
+	}
+
+	if n != int64(len(content)) {
+		t.Errorf("copyFile() copied %v bytes, want %v", n, len(content))
+	}
+
+	info, e := os.Stat(dst)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("copyFile() mode = %v, want %v", info.Mode().Perm(), os.FileMode(0755))
+	}
+}
+
+func TestPrintPathOutside(t *testing.T) {
+	fixture := tmpDir + PS + "path-outside-01"
+	if e := os.MkdirAll(fixture+PS+"{{.dir}}", dirMode); e != nil {
+		t.Fatal(e)
+	}
+	if e := os.WriteFile(fixture+PS+"{{.dir}}"+PS+"{{.name}}.txt", []byte("{{.name}}\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		wantErr bool
+	}{
+		{"inside", map[string]string{"dir": "docs", "name": "a"}, false},
+		{"inside-after-clean", map[string]string{"dir": "docs/../src", "name": "a"}, false},
+		{"name", map[string]string{"dir": "docs", "name": "../../../escaped"}, true},
+		{"dir", map[string]string{"dir": "../..", "name": "escaped"}, true},
+		{"absolute", map[string]string{"dir": "/tmp", "name": "escaped"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outDir := tmpDir + PS + "path-outside-" + tt.name + PS + "out"
+
+			err := Print(fixture, outDir, tt.vars, &TmplManifest{Version: SchemaVersion}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Print() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !strings.Contains(err.Error(), "{{.dir}}") {
+				t.Errorf("Print() error = %v, want it to name the template file", err)
 			}
 
-			if tt.want && !fsio.Exist(saveDir+PS+tt.file) {
-				t.Errorf("copyAsIs() did not copy %v", tt.file)
+			escaped, _ := filepath.Glob(tmpDir + PS + "escaped*")
+			escaped2, _ := filepath.Glob(tmpDir + PS + "path-outside-" + tt.name + PS + "escaped*")
+			if len(escaped)+len(escaped2) > 0 {
+				t.Errorf("Print() wrote outside of the output directory: %v", append(escaped, escaped2...))
 			}
 		})
 	}
@@ -0,0 +1,578 @@
+package press
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schemas that documents are validated against, see UseSchemas.
+var (
+	answersSchema []byte
+	tmplSchema    []byte
+)
+
+// UseSchemas Set the schemas that answers files and manifests are validated
+// against. The schema files live at the root of the repository, so that they
+// can be referenced by URL, where only the main package can embed them.
+func UseSchemas(answers, tmpl []byte) {
+	answersSchema = answers
+	tmplSchema = tmpl
+}
+
+// SchemaError A single location in a document that does not conform to a
+// schema. Location is a JSON pointer (RFC 6901) to the offending value.
+type SchemaError struct {
+	Location string
+	Message  string
+}
+
+func (se *SchemaError) Error() string {
+	loc := se.Location
+	if loc == "" {
+		loc = "/"
+	}
+
+	return fmt.Sprintf(msg.Stderr.SchemaViolation, loc, se.Message)
+}
+
+// schemaKeywords The JSON Schema (draft 2020-12) keywords the validator knows.
+// A schema with any other keyword is rejected, rather than the keyword being
+// quietly ignored and its constraint never enforced.
+var schemaKeywords = map[string]bool{
+	// Applied to the value.
+	"$ref": true, "additionalProperties": true, "allOf": true, "anyOf": true,
+	"const": true, "dependentRequired": true, "else": true, "enum": true,
+	"exclusiveMaximum": true, "exclusiveMinimum": true, "format": true,
+	"if": true, "items": true, "maxItems": true, "maxLength": true,
+	"maximum": true, "minItems": true, "minLength": true, "minimum": true,
+	"multipleOf": true, "not": true, "pattern": true, "patternProperties": true,
+	"properties": true, "propertyNames": true, "required": true, "then": true,
+	"type": true, "uniqueItems": true,
+	// Annotations, which do not constrain the value. The version is that of
+	// the schemas in this repository.
+	"$anchor": true, "$comment": true, "$defs": true, "$id": true,
+	"$schema": true, "default": true, "deprecated": true, "description": true,
+	"examples": true, "title": true, "version": true,
+}
+
+// schemaValidator Validates a document against the subset of JSON Schema
+// (draft 2020-12) keywords used by the schemas in this repository.
+type schemaValidator struct {
+	errs []*SchemaError
+	root map[string]interface{}
+}
+
+// ValidateAnswersSchema Check the content of an answers file conforms to the
+// answers schema.
+func ValidateAnswersSchema(content []byte) ([]*SchemaError, error) {
+	return ValidateSchema(answersSchema, content)
+}
+
+// ValidateManifestSchema Check the content of a template manifest conforms to
+// the template schema.
+func ValidateManifestSchema(content []byte) ([]*SchemaError, error) {
+	return ValidateSchema(tmplSchema, content)
+}
+
+// ValidateSchema Validate a JSON document against a JSON schema. An error is
+// only returned when either the schema or the document cannot be decoded; any
+// violations are returned as a list.
+func ValidateSchema(schema, content []byte) ([]*SchemaError, error) {
+	if len(schema) == 0 {
+		return nil, fmt.Errorf(msg.Stderr.SchemaNotLoaded)
+	}
+
+	var root map[string]interface{}
+	if e := decodeJson(schema, &root); e != nil {
+		return nil, fmt.Errorf(msg.Stderr.SchemaDecode, e.Error())
+	}
+
+	if e := checkKeywords(root, "#"); e != nil {
+		return nil, e
+	}
+
+	var doc interface{}
+	if e := decodeJson(content, &doc); e != nil {
+		return nil, fmt.Errorf(msg.Stderr.SchemaDocDecode, e.Error())
+	}
+
+	sv := &schemaValidator{root: root}
+	sv.check(root, doc, "")
+
+	return sv.errs, nil
+}
+
+// SchemaErrors Join a list of schema violations into a single error.
+func SchemaErrors(filename string, errs []*SchemaError) error {
+	if len(errs) < 1 {
+		return nil
+	}
+
+	lines := make([]string, len(errs))
+	for i, se := range errs {
+		lines[i] = "\t" + se.Error()
+	}
+
+	return fmt.Errorf(msg.Stderr.SchemaViolations, filename, strings.Join(lines, "\n"))
+}
+
+// check Apply all keywords of a schema to a value.
+func (sv *schemaValidator) check(schema map[string]interface{}, value interface{}, ptr string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, found := sv.resolve(ref)
+		if !found {
+			sv.fail(ptr, msg.Stderr.SchemaBadRef, ref)
+			return
+		}
+		sv.check(resolved, value, ptr)
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, value) {
+		sv.fail(ptr, msg.Stderr.SchemaType, t, jsonType(value))
+		return
+	}
+
+	if c, ok := schema["const"]; ok && !jsonEqual(c, value) {
+		sv.fail(ptr, msg.Stderr.SchemaConst, c)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range enum {
+			if jsonEqual(v, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			sv.fail(ptr, msg.Stderr.SchemaEnum, value, enum)
+		}
+	}
+
+	if sub, ok := schema["not"].(map[string]interface{}); ok {
+		if len(sv.sub(sub, value, ptr)) == 0 {
+			sv.fail(ptr, msg.Stderr.SchemaNot)
+		}
+	}
+
+	if list, ok := schema["anyOf"].([]interface{}); ok {
+		sv.checkAnyOf(list, value, ptr)
+	}
+
+	if list, ok := schema["allOf"].([]interface{}); ok {
+		for _, s := range list {
+			if sub, ok2 := s.(map[string]interface{}); ok2 {
+				sv.check(sub, value, ptr)
+			}
+		}
+	}
+
+	if cond, ok := schema["if"].(map[string]interface{}); ok {
+		if len(sv.sub(cond, value, ptr)) == 0 {
+			if then, ok2 := schema["then"].(map[string]interface{}); ok2 {
+				sv.check(then, value, ptr)
+			}
+		} else if els, ok2 := schema["else"].(map[string]interface{}); ok2 {
+			sv.check(els, value, ptr)
+		}
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		sv.checkNumber(schema, v, ptr)
+	case map[string]interface{}:
+		sv.checkObject(schema, v, ptr)
+	case []interface{}:
+		sv.checkArray(schema, v, ptr)
+	case string:
+		sv.checkString(schema, v, ptr)
+	}
+}
+
+// checkAnyOf Passes when at least one of the schemas passes.
+func (sv *schemaValidator) checkAnyOf(list []interface{}, value interface{}, ptr string) {
+	var best []*SchemaError
+
+	for _, s := range list {
+		sub, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		errs := sv.sub(sub, value, ptr)
+		if len(errs) == 0 {
+			return
+		}
+
+		if best == nil || len(errs) < len(best) {
+			best = errs
+		}
+	}
+
+	// Report the violations of the closest match, which is usually the most
+	// helpful to the reader.
+	sv.errs = append(sv.errs, best...)
+}
+
+func (sv *schemaValidator) checkArray(schema map[string]interface{}, arr []interface{}, ptr string) {
+	if n, ok := schemaInt(schema["minItems"]); ok && len(arr) < n {
+		sv.fail(ptr, msg.Stderr.SchemaMinItems, n)
+	}
+
+	if n, ok := schemaInt(schema["maxItems"]); ok && len(arr) > n {
+		sv.fail(ptr, msg.Stderr.SchemaMaxItems, n)
+	}
+
+	if u, ok := schema["uniqueItems"].(bool); ok && u {
+		for i := 0; i < len(arr); i++ {
+			for j := i + 1; j < len(arr); j++ {
+				if jsonEqual(arr[i], arr[j]) {
+					sv.fail(fmt.Sprintf("%v/%d", ptr, j), msg.Stderr.SchemaUniqueItems, i)
+				}
+			}
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range arr {
+			sv.check(items, item, fmt.Sprintf("%v/%d", ptr, i))
+		}
+	}
+}
+
+func (sv *schemaValidator) checkNumber(schema map[string]interface{}, num json.Number, ptr string) {
+	n, _ := num.Float64()
+
+	if m, ok := schemaFloat(schema["minimum"]); ok && n < m {
+		sv.fail(ptr, msg.Stderr.SchemaMinimum, num, schema["minimum"])
+	}
+
+	if m, ok := schemaFloat(schema["exclusiveMinimum"]); ok && n <= m {
+		sv.fail(ptr, msg.Stderr.SchemaExclusiveMinimum, num, schema["exclusiveMinimum"])
+	}
+
+	if m, ok := schemaFloat(schema["maximum"]); ok && n > m {
+		sv.fail(ptr, msg.Stderr.SchemaMaximum, num, schema["maximum"])
+	}
+
+	if m, ok := schemaFloat(schema["exclusiveMaximum"]); ok && n >= m {
+		sv.fail(ptr, msg.Stderr.SchemaExclusiveMaximum, num, schema["exclusiveMaximum"])
+	}
+
+	if m, ok := schemaFloat(schema["multipleOf"]); ok && m > 0 {
+		if q := n / m; q != math.Trunc(q) {
+			sv.fail(ptr, msg.Stderr.SchemaMultipleOf, num, schema["multipleOf"])
+		}
+	}
+}
+
+func (sv *schemaValidator) checkObject(schema map[string]interface{}, obj map[string]interface{}, ptr string) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, found := obj[name]; !found {
+				sv.fail(ptr, msg.Stderr.SchemaRequired, name)
+			}
+		}
+	}
+
+	if deps, ok := schema["dependentRequired"].(map[string]interface{}); ok {
+		for name, list := range deps {
+			if _, found := obj[name]; !found {
+				continue
+			}
+			names, _ := list.([]interface{})
+			for _, r := range names {
+				dep, _ := r.(string)
+				if _, found := obj[dep]; !found {
+					sv.fail(ptr, msg.Stderr.SchemaDependent, dep, name)
+				}
+			}
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]interface{})
+	patterns, _ := schema["patternProperties"].(map[string]interface{})
+	propNames, _ := schema["propertyNames"].(map[string]interface{})
+
+	// Go through the keys in order, so that errors are reported consistently.
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		loc := ptr + "/" + escapePointer(k)
+
+		if propNames != nil {
+			sv.check(propNames, k, loc)
+		}
+
+		matched := false
+
+		if sub, ok := props[k].(map[string]interface{}); ok {
+			sv.check(sub, obj[k], loc)
+			matched = true
+		}
+
+		for pattern, s := range patterns {
+			re, e := regexp.Compile(pattern)
+			if e != nil || !re.MatchString(k) {
+				continue
+			}
+			if sub, ok := s.(map[string]interface{}); ok {
+				sv.check(sub, obj[k], loc)
+			}
+			matched = true
+		}
+
+		if matched {
+			continue
+		}
+
+		switch ap := schema["additionalProperties"].(type) {
+		case bool:
+			if !ap {
+				sv.fail(loc, msg.Stderr.SchemaUnknownProperty, k)
+			}
+		case map[string]interface{}:
+			sv.check(ap, obj[k], loc)
+		}
+	}
+}
+
+func (sv *schemaValidator) checkString(schema map[string]interface{}, str string, ptr string) {
+	if n, ok := schemaInt(schema["minLength"]); ok && len([]rune(str)) < n {
+		sv.fail(ptr, msg.Stderr.SchemaMinLength, n)
+	}
+
+	if n, ok := schemaInt(schema["maxLength"]); ok && len([]rune(str)) > n {
+		sv.fail(ptr, msg.Stderr.SchemaMaxLength, n)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, e := regexp.Compile(pattern)
+		if e != nil {
+			sv.fail(ptr, msg.Stderr.InvalidRegExp, pattern, e.Error())
+		} else if !re.MatchString(str) {
+			sv.fail(ptr, msg.Stderr.SchemaPattern, str, pattern)
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && format == "regex" {
+		if _, e := regexp.Compile(str); e != nil {
+			sv.fail(ptr, msg.Stderr.InvalidRegExp, str, e.Error())
+		}
+	}
+}
+
+func (sv *schemaValidator) fail(ptr, format string, vars ...interface{}) {
+	sv.errs = append(sv.errs, &SchemaError{
+		Location: ptr,
+		Message:  fmt.Sprintf(format, vars...),
+	})
+}
+
+// resolve Look up a local reference, such as "#/$defs/validator", or an
+// anchor, such as "#validator".
+func (sv *schemaValidator) resolve(ref string) (map[string]interface{}, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+
+	if !strings.HasPrefix(ref, "#/") {
+		return findAnchor(sv.root, ref[1:])
+	}
+
+	var node interface{} = sv.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		node, ok = m[token]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	s, ok := node.(map[string]interface{})
+
+	return s, ok
+}
+
+// sub Run a schema against a value without recording the violations.
+func (sv *schemaValidator) sub(schema map[string]interface{}, value interface{}, ptr string) []*SchemaError {
+	tmp := &schemaValidator{root: sv.root}
+	tmp.check(schema, value, ptr)
+
+	return tmp.errs
+}
+
+// decodeJson Decode JSON keeping numbers as they are written.
+func decodeJson(content []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(content))
+	d.UseNumber()
+
+	return d.Decode(v)
+}
+
+// checkKeywords Reject a schema that uses a keyword the validator does not
+// know, naming where it is in the schema.
+func checkKeywords(schema interface{}, ptr string) error {
+	node, ok := schema.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(node))
+	for k := range node {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !schemaKeywords[k] {
+			return fmt.Errorf(msg.Stderr.SchemaKeyword, k, ptr)
+		}
+
+		loc := ptr + "/" + escapePointer(k)
+
+		switch k {
+		// A schema for each name.
+		case "$defs", "patternProperties", "properties":
+			subs, _ := node[k].(map[string]interface{})
+			for name, s := range subs {
+				if e := checkKeywords(s, loc+"/"+escapePointer(name)); e != nil {
+					return e
+				}
+			}
+		// A list of schemas.
+		case "allOf", "anyOf":
+			subs, _ := node[k].([]interface{})
+			for i, s := range subs {
+				if e := checkKeywords(s, fmt.Sprintf("%v/%d", loc, i)); e != nil {
+					return e
+				}
+			}
+		// A single schema.
+		case "additionalProperties", "else", "if", "items", "not", "propertyNames", "then":
+			if e := checkKeywords(node[k], loc); e != nil {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+// escapePointer Escape a key for use as a JSON pointer token.
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func findAnchor(node map[string]interface{}, anchor string) (map[string]interface{}, bool) {
+	if a, ok := node["$anchor"].(string); ok && a == anchor {
+		return node, true
+	}
+
+	for _, v := range node {
+		if m, ok := v.(map[string]interface{}); ok {
+			if found, ok2 := findAnchor(m, anchor); ok2 {
+				return found, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func jsonEqual(a, b interface{}) bool {
+	if na, ok := a.(json.Number); ok {
+		if nb, ok2 := b.(json.Number); ok2 {
+			fa, _ := na.Float64()
+			fb, _ := nb.Float64()
+			return fa == fb
+		}
+		return false
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// jsonType Name the JSON type of a decoded value.
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, e := v.Int64(); e == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "unknown"
+}
+
+// matchesType Check a value against the "type" keyword, which may be a single
+// type or a list of types.
+func matchesType(t interface{}, value interface{}) bool {
+	actual := jsonType(value)
+
+	var types []interface{}
+	switch tt := t.(type) {
+	case string:
+		types = []interface{}{tt}
+	case []interface{}:
+		types = tt
+	}
+
+	for _, x := range types {
+		name, _ := x.(string)
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func schemaFloat(v interface{}) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	f, e := n.Float64()
+
+	return f, e == nil
+}
+
+func schemaInt(v interface{}) (int, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	i, e := n.Int64()
+
+	return int(i), e == nil
+}
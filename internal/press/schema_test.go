@@ -0,0 +1,149 @@
+package press
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateManifestSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			"valid",
+			`{"version": "2.2.0", "placeholders": {"appName": "name"}, "skip": ["*.md"]}`,
+			nil,
+		},
+		{
+			"unknown-property",
+			`{"version": "2.2.0", "copyAsis": ["*.png"]}`,
+			[]string{"/copyAsis"},
+		},
+		{
+			"wrong-type",
+			`{"version": "2.2.0", "skip": "*.md", "placeholders": {"appName": 1}}`,
+			[]string{"/placeholders/appName", "/skip"},
+		},
+		{
+			"missing-version",
+			`{"placeholders": {}}`,
+			[]string{""},
+		},
+		{
+			"validator",
+			`{"version": "2.2.0", "validation": [{"fields": ["a"], "rule": "regExp"}, {"fields": ["a"], "rule": "nope"}]}`,
+			[]string{"/validation/0", "/validation/1/rule"},
+		},
+		{
+			"duplicate-items",
+			`{"version": "2.2.0", "skip": ["a", "a"]}`,
+			[]string{"/skip/1"},
+		},
+		{
+			"filenames",
+			`{"version": "2.14.0", "filenames": {"dotPrefixes": ["dot_", "a/"], "stripSuffixes": [".tmpl"], "other": 1}}`,
+			[]string{"/filenames/dotPrefixes/1", "/filenames/other"},
+		},
+		{
+			"minimum",
+			`{"version": "2.15.0", "maxRenderSize": 0}`,
+			[]string{"/maxRenderSize"},
+		},
+		{
+			"data-outside",
+			`{"version": "2.15.0", "data": ["../secrets.json", "/etc/a.yaml", "a/../../b.yml", "..data/a..b.json"]}`,
+			[]string{"/data/0", "/data/1", "/data/2"},
+		},
+		{
+			"partials-outside",
+			`{"version": "2.15.0", "partials": "../_partials"}`,
+			[]string{"/partials"},
+		},
+		{
+			"reserved-item",
+			`{"version": "2.15.0", "forEach": [{"as": "Press", "files": ["a"], "list": "envs", "path": "a"}]}`,
+			[]string{"/forEach/0/as"},
+		},
+		{
+			"escaped-pointer",
+			`{"version": "2.2.0", "a/b~c": true}`,
+			[]string{"/a~1b~0c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := ValidateManifestSchema([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("ValidateManifestSchema() error = %v", err)
+			}
+
+			var got []string
+			for _, se := range errs {
+				got = append(got, se.Location)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got locations %v, want %v; errors: %v", got, tt.want, errs)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaBadInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  []byte
+		content string
+	}{
+		{"no-schema", nil, `{}`},
+		{"bad-document", tmplSchema, `{"version": }`},
+		{"unknown-keyword", []byte(`{"properties": {"a": {"type": "string", "maxLenght": 3}}}`), `{}`},
+		{"unknown-keyword-in-list", []byte(`{"anyOf": [{"oneOf": []}]}`), `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ValidateSchema(tt.schema, []byte(tt.content)); err == nil {
+				t.Errorf("ValidateSchema() want an error")
+			}
+		})
+	}
+}
+
+func TestValidateSchemaNumbers(t *testing.T) {
+	schema := []byte(`{"type": "number", "minimum": 1, "exclusiveMaximum": 10, "multipleOf": 0.5}`)
+
+	tests := []struct {
+		content string
+		wantErr bool
+	}{
+		{"1", false},
+		{"9.5", false},
+		{"0.5", true},
+		{"10", true},
+		{"2.25", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			errs, err := ValidateSchema(schema, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ValidateSchema() error = %v", err)
+			}
+
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("ValidateSchema() got %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadAnswersSchema(t *testing.T) {
+	_, err := LoadAnswers(fixtureDir + PS + "answers-03.json")
+	if err == nil {
+		t.Errorf("LoadAnswers() want an error for an unknown property")
+	}
+}
@@ -1,23 +1,10 @@
 package press
 
-import (
-	"github.com/kohirens/stdlib/fsio"
-	"github.com/ryanuber/go-glob"
-)
-
-// Get to know glob before you question the correctness of my program:
-// https://man7.org/linux/man-pages/man7/glob.7.html
+// InSkipArray Indicates a file matches the patterns, which have the semantics
+// of a .gitignore file, see Patterns. The path is relative to the root of the
+// template.
 func InSkipArray(pathToFile string, skips []string) bool {
-	skip := false
-
-	for _, pattern := range skips {
-		pattern = fsio.Normalize(pattern)
-
-		if glob.Glob(pattern, pathToFile) {
-			skip = true
-			break
-		}
-	}
+	_, skip := NewPatterns(skips).Match(pathToFile, false)
 
 	return skip
 }
@@ -0,0 +1,187 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/stdlib/fsio"
+	"github.com/kohirens/stdlib/log"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// reportSuffix Added to the name of the output directory for the report
+	// of a press that failed.
+	reportSuffix = ".tmplpress-failed.txt"
+	// stagingSuffix Added to the name of the output directory for the
+	// directory a template is pressed into before it is moved into place.
+	stagingSuffix = ".tmplpress-"
+)
+
+// newStaging Make a directory next to the output directory to press the
+// template into, so that it is on the same file system and can be renamed.
+func newStaging(outDir string) (string, error) {
+	parent := filepath.Dir(outDir)
+	if e := os.MkdirAll(parent, dirMode); e != nil {
+		return "", fmt.Errorf(msg.Stderr.Staging, outDir, e.Error())
+	}
+
+	staging, e1 := os.MkdirTemp(parent, "."+filepath.Base(outDir)+stagingSuffix)
+	if e1 != nil {
+		return "", fmt.Errorf(msg.Stderr.Staging, outDir, e1.Error())
+	}
+
+	// Temporary directories are only for the owner, give it the same mode as
+	// any other directory of the output.
+	if e := os.Chmod(staging, dirMode); e != nil {
+		_ = os.RemoveAll(staging)
+		return "", fmt.Errorf(msg.Stderr.Staging, outDir, e.Error())
+	}
+
+	log.Dbugf(msg.Stdout.Staging, staging)
+
+	return staging, nil
+}
+
+// commitStaging Move the pressed files into the output directory. When it
+// does not exist the staging directory is renamed, otherwise each file is
+// moved into it, following the policy for the files it already has. When a
+// move fails, those already made are undone, so the output directory is as it
+// was. A report left by a press that failed before is removed.
+func commitStaging(staging, outDir string, policies map[string]string) error {
+	_ = os.Remove(ReportPath(outDir))
+
+	if !fsio.Exist(outDir) {
+		if e := os.Rename(staging, outDir); e != nil {
+			return fmt.Errorf(msg.Stderr.CommitStaging, staging, outDir, e.Error())
+		}
+		return nil
+	}
+
+	j := &journal{}
+
+	e1 := filepath.Walk(staging, func(sourcePath string, fi os.FileInfo, wErr error) error {
+		if wErr != nil {
+			return wErr
+		}
+
+		dstPath := outDir + strings.TrimPrefix(sourcePath, staging)
+
+		if fi.IsDir() {
+			return j.mkdir(dstPath)
+		}
+
+		outPath := filepath.ToSlash(strings.TrimLeft(strings.TrimPrefix(sourcePath, staging), "\\/"))
+
+		switch policies[outPath] {
+		case ConflictSkip:
+			log.Infof(msg.Stdout.ConflictSkip, outPath)
+			return nil
+		case ConflictBackup:
+			backup := backupPath(dstPath)
+			log.Infof(msg.Stdout.ConflictBackup, outPath, filepath.ToSlash(strings.TrimLeft(strings.TrimPrefix(backup, outDir), "\\/")))
+			if e := j.rename(dstPath, backup); e != nil {
+				return e
+			}
+		}
+
+		return j.rename(sourcePath, dstPath)
+	})
+	if e1 != nil {
+		j.undo()
+		return fmt.Errorf(msg.Stderr.CommitStaging, staging, outDir, e1.Error())
+	}
+
+	return os.RemoveAll(staging)
+}
+
+// journal The changes made to the output directory while committing, so they
+// can be undone.
+type journal struct {
+	dirs    []string    // made, that did not exist.
+	renames [][2]string // from and to, in the order made.
+}
+
+func (j *journal) mkdir(dir string) error {
+	if fsio.Exist(dir) {
+		return nil
+	}
+
+	if e := os.Mkdir(dir, dirMode); e != nil {
+		return e
+	}
+	j.dirs = append(j.dirs, dir)
+
+	return nil
+}
+
+func (j *journal) rename(from, to string) error {
+	if e := os.Rename(from, to); e != nil {
+		return e
+	}
+	j.renames = append(j.renames, [2]string{from, to})
+
+	return nil
+}
+
+// undo Reverse the changes, newest first. Ones that cannot be undone are
+// warned about, as there is nothing more to do.
+func (j *journal) undo() {
+	for i := len(j.renames) - 1; i >= 0; i-- {
+		r := j.renames[i]
+		if e := os.Rename(r[1], r[0]); e != nil {
+			log.Warnf(msg.Stderr.UndoCommit, r[1], r[0], e.Error())
+		}
+	}
+
+	for i := len(j.dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(j.dirs[i])
+	}
+}
+
+// backupPath A name for the backup of a file that is not taken, the file with
+// the backup suffix, else with a number after it, so no backup is replaced.
+func backupPath(dstPath string) string {
+	backup := dstPath + backupSuffix
+	for i := 1; exists(backup); i++ {
+		backup = fmt.Sprintf("%v%v.%d", dstPath, backupSuffix, i)
+	}
+
+	return backup
+}
+
+// rollbackStaging Remove the staging directory of a press that failed, and
+// write a report of the file that failed and why, next to the output
+// directory. Returns the error to show, with where the report is.
+func rollbackStaging(staging, outDir, relativePath string, pErr error) error {
+	if e := os.RemoveAll(staging); e != nil {
+		log.Warnf(msg.Stderr.RemoveStaging, staging, e.Error())
+	}
+
+	report := ReportPath(outDir)
+	if relativePath == "" {
+		relativePath = outDir
+	}
+	content := fmt.Sprintf(msg.Stdout.FailureReport, outDir, relativePath, pErr.Error())
+
+	if e := os.WriteFile(report, []byte(content), 0644); e != nil {
+		log.Warnf(msg.Stderr.CannotWriteReport, report, e.Error())
+		return fmt.Errorf(msg.Stderr.PressFailed, relativePath, pErr.Error())
+	}
+
+	return fmt.Errorf(msg.Stderr.PressFailedReport, relativePath, pErr.Error(), report)
+}
+
+// exists Indicates there is a file, directory, or link at the path, even a
+// link to nothing.
+func exists(path string) bool {
+	_, e := os.Lstat(path)
+
+	return e == nil
+}
+
+// ReportPath The report of a press into the output directory that failed.
+func ReportPath(outDir string) string {
+	return filepath.Clean(outDir) + reportSuffix
+}
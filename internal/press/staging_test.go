@@ -0,0 +1,191 @@
+package press
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrintRollback(t *testing.T) {
+	fixture := fixtureDir + PS + "staging-01"
+	outDir := tmpDir + PS + "staging-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	e2 := Print(fixture, outDir, map[string]string{"name": "tmplpress"}, tm, nil)
+	if e2 == nil {
+		t.Fatal("Print() did not fail on a file that cannot be parsed")
+	}
+
+	if _, e := os.Stat(outDir); !os.IsNotExist(e) {
+		t.Errorf("Print() left the output directory %v", outDir)
+	}
+
+	staged, _ := filepath.Glob(tmpDir + PS + ".staging-01" + stagingSuffix + "*")
+	if len(staged) > 0 {
+		t.Errorf("Print() left the staging directory %v", staged)
+	}
+
+	report, e3 := os.ReadFile(ReportPath(outDir))
+	if e3 != nil {
+		t.Fatalf("Print() did not write a report: %v", e3)
+	}
+
+	if !strings.Contains(string(report), "file: b.txt") || !strings.Contains(string(report), "noSuchFunc") {
+		t.Errorf("report = %q, want the file that failed and why", report)
+	}
+}
+
+func TestPrintInterrupted(t *testing.T) {
+	fixture := fixtureDir + PS + "meta-01"
+	outDir := tmpDir + PS + "interrupted-01"
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	stop, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e2 := Print(fixture, outDir, map[string]string{"owner": "Kohirens"}, tm, &PrintOptions{Context: stop})
+	if e2 == nil || !strings.Contains(e2.Error(), "interrupted") {
+		t.Fatalf("Print() error = %v, want it interrupted", e2)
+	}
+
+	if _, e := os.Stat(outDir); !os.IsNotExist(e) {
+		t.Errorf("Print() left the output directory %v", outDir)
+	}
+}
+
+func Test_commitStaging(t *testing.T) {
+	outDir := tmpDir + PS + "commit-staging-01"
+
+	if e := os.MkdirAll(outDir, dirMode); e != nil {
+		t.Fatal(e)
+	}
+	_ = os.WriteFile(outDir+PS+"keep.txt", []byte("keep"), 0644)
+	_ = os.WriteFile(ReportPath(outDir), []byte("failed before"), 0644)
+
+	staging, e1 := newStaging(outDir)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+	_ = os.MkdirAll(staging+PS+"sub", dirMode)
+	_ = os.WriteFile(staging+PS+"sub"+PS+"new.txt", []byte("new"), 0644)
+
+	if e := commitStaging(staging, outDir, nil); e != nil {
+		t.Fatalf("commitStaging() error = %v", e)
+	}
+
+	for _, f := range []string{"keep.txt", "sub" + PS + "new.txt"} {
+		if _, e := os.Stat(outDir + PS + f); e != nil {
+			t.Errorf("commitStaging() lost %v: %v", f, e)
+		}
+	}
+
+	if _, e := os.Stat(staging); !os.IsNotExist(e) {
+		t.Errorf("commitStaging() left the staging directory %v", staging)
+	}
+
+	if _, e := os.Stat(ReportPath(outDir)); !os.IsNotExist(e) {
+		t.Errorf("commitStaging() left the report of a failed press")
+	}
+}
+
+func Test_commitStagingUndo(t *testing.T) {
+	outDir := tmpDir + PS + "commit-staging-02"
+	_ = os.RemoveAll(outDir)
+
+	// A file cannot be moved over a directory that has files.
+	if e := os.MkdirAll(outDir+PS+"z.txt", dirMode); e != nil {
+		t.Fatal(e)
+	}
+	_ = os.WriteFile(outDir+PS+"z.txt"+PS+"in-the-way.txt", []byte("in the way"), 0644)
+	_ = os.WriteFile(outDir+PS+"a.txt", []byte("existing a"), 0644)
+
+	staging, e1 := newStaging(outDir)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+	_ = os.MkdirAll(staging+PS+"sub", dirMode)
+	_ = os.WriteFile(staging+PS+"a.txt", []byte("a"), 0644)
+	_ = os.WriteFile(staging+PS+"sub"+PS+"b.txt", []byte("b"), 0644)
+	_ = os.WriteFile(staging+PS+"z.txt", []byte("z"), 0644)
+
+	if e := commitStaging(staging, outDir, map[string]string{"a.txt": ConflictBackup}); e == nil {
+		t.Fatal("commitStaging() want an error moving a file over a directory")
+	}
+
+	if got, _ := os.ReadFile(outDir + PS + "a.txt"); string(got) != "existing a" {
+		t.Errorf("a.txt = %q, want the backup moved back", got)
+	}
+
+	for _, f := range []string{"a.txt" + backupSuffix, "sub"} {
+		if _, e := os.Lstat(outDir + PS + f); !os.IsNotExist(e) {
+			t.Errorf("commitStaging() did not undo %v", f)
+		}
+	}
+
+	if _, e := os.Stat(staging + PS + "sub" + PS + "b.txt"); e != nil {
+		t.Errorf("commitStaging() did not move sub/b.txt back to staging: %v", e)
+	}
+}
+
+func TestPrintTypeCollision(t *testing.T) {
+	fixture := fixtureDir + PS + "conflict-01"
+	outDir := tmpDir + PS + "type-collision-01"
+	_ = os.RemoveAll(outDir)
+
+	if e := os.MkdirAll(outDir+PS+"a.txt", dirMode); e != nil {
+		t.Fatal(e)
+	}
+
+	tm, e1 := ReadTemplateJson(fixture + PS + TmplManifestFile)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	e2 := Print(fixture, outDir, map[string]string{"name": "press"}, tm, &PrintOptions{
+		Conflicts: []*ConflictRule{{Policy: ConflictOverwrite}},
+	})
+	if e2 == nil || !strings.Contains(e2.Error(), "a.txt") {
+		t.Fatalf("Print() error = %v, want a collision of a.txt", e2)
+	}
+
+	if _, e := os.Stat(outDir + PS + "new.txt"); !os.IsNotExist(e) {
+		t.Errorf("Print() moved files into %v before failing", outDir)
+	}
+
+	staged, _ := filepath.Glob(tmpDir + PS + ".type-collision-01" + stagingSuffix + "*")
+	if len(staged) > 0 {
+		t.Errorf("Print() left the staging directory %v", staged)
+	}
+
+	if _, e := os.Stat(ReportPath(outDir)); e != nil {
+		t.Errorf("Print() did not write a report: %v", e)
+	}
+}
+
+func Test_backupPath(t *testing.T) {
+	dir := tmpDir + PS + "backup-path-01"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir, dirMode)
+
+	dstPath := dir + PS + "a.txt"
+	if got := backupPath(dstPath); got != dstPath+backupSuffix {
+		t.Errorf("backupPath() = %v, want %v", got, dstPath+backupSuffix)
+	}
+
+	_ = os.WriteFile(dstPath+backupSuffix, []byte("first backup"), 0644)
+	_ = os.WriteFile(dstPath+backupSuffix+".1", []byte("second backup"), 0644)
+
+	if got := backupPath(dstPath); got != dstPath+backupSuffix+".2" {
+		t.Errorf("backupPath() = %v, want %v", got, dstPath+backupSuffix+".2")
+	}
+}
@@ -0,0 +1,96 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/stdlib/log"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Policies for the symbolic links of a template.
+const (
+	// SymlinksFollow Output the file a link points to, which must be in the
+	// template.
+	SymlinksFollow = "follow"
+	// SymlinksPreserve Make a link, with the placeholders in its target
+	// filled in, which must point inside the output directory. The default.
+	SymlinksPreserve = "preserve"
+)
+
+// PreservesLink Indicates the file is a symbolic link that is output as a
+// link, so it has no content of its own to press.
+func (tm *TmplManifest) PreservesLink(d fs.DirEntry) bool {
+	return d.Type()&fs.ModeSymlink != 0 && tm.Symlinks != SymlinksFollow
+}
+
+// checkFollow Verify a link can be followed, to a file that is in the
+// template.
+func checkFollow(tplDir, sourcePath, relativePath string) error {
+	target, e1 := filepath.EvalSymlinks(sourcePath)
+	if e1 != nil {
+		return fmt.Errorf(msg.Stderr.LinkBroken, relativePath, e1.Error())
+	}
+
+	root, e2 := filepath.EvalSymlinks(tplDir)
+	if e2 != nil {
+		return e2
+	}
+
+	if !isInside(root, target) {
+		return fmt.Errorf(msg.Stderr.LinkOutsideTemplate, relativePath, target)
+	}
+
+	info, e3 := os.Stat(target)
+	if e3 != nil {
+		return e3
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf(msg.Stderr.LinkToDir, relativePath)
+	}
+
+	return nil
+}
+
+// isInside Indicates the path is the directory, or in it.
+func isInside(dir, path string) bool {
+	rel, e := filepath.Rel(dir, path)
+
+	return e == nil && rel != ".." && !strings.HasPrefix(rel, ".."+PS) && !filepath.IsAbs(rel)
+}
+
+// planLink Plan to make a link like the one in the template, with the
+// placeholders in its target filled in.
+func (p *printer) planLink(sourcePath, relativePath, outPath string, data interface{}) error {
+	target, e1 := os.Readlink(sourcePath)
+	if e1 != nil {
+		return fmt.Errorf(msg.Stderr.LinkBroken, relativePath, e1.Error())
+	}
+
+	rendered, e2 := renderPath(target, data)
+	if e2 != nil {
+		return e2
+	}
+
+	dstFile := filepath.Clean(p.outDir + PS + outPath)
+
+	// A link is resolved from the directory it is in, it must not lead out
+	// of the output.
+	if filepath.IsAbs(rendered) || !isInside(p.outDir, filepath.Join(filepath.Dir(dstFile), rendered)) {
+		return fmt.Errorf(msg.Stderr.LinkOutsideOutput, relativePath, rendered)
+	}
+
+	if other, ok := p.printed[dstFile]; ok {
+		return fmt.Errorf(msg.Stderr.OutputCollision, other, relativePath, outPath)
+	}
+	p.printed[dstFile] = relativePath
+
+	log.Infof(msg.Stdout.Link, relativePath, rendered)
+
+	p.ops = append(p.ops, &pressOp{dstFile: dstFile, kind: opLink, relativePath: relativePath, target: rendered})
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package press
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// makeLinks Make a template in the temporary directory with the links, by
+// their path to their target.
+func makeLinks(t *testing.T, name string, links map[string]string) string {
+	t.Helper()
+
+	fixture := tmpDir + PS + name
+	if e := os.MkdirAll(fixture+PS+"docs", dirMode); e != nil {
+		t.Fatal(e)
+	}
+	if e := os.WriteFile(fixture+PS+"docs"+PS+"README.md", []byte("# {{.name}}\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+
+	for link, target := range links {
+		if e := os.Symlink(target, fixture+PS+link); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	return fixture
+}
+
+func TestPrintSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("making links needs privileges on Windows")
+	}
+
+	tests := []struct {
+		name       string
+		symlinks   string
+		links      map[string]string
+		wantErr    string
+		wantLink   string
+		wantTarget string
+		wantFile   string
+	}{
+		{
+			"preserve",
+			"",
+			map[string]string{"README.md": "docs/README.md"},
+			"",
+			"README.md",
+			"docs/README.md",
+			"",
+		},
+		{
+			"preserve-templated-target",
+			SymlinksPreserve,
+			map[string]string{"current": "docs/{{.name}}.md"},
+			"",
+			"current",
+			"docs/tmplpress.md",
+			"",
+		},
+		{
+			"preserve-dir",
+			SymlinksPreserve,
+			map[string]string{"manual": "docs"},
+			"",
+			"manual",
+			"docs",
+			"",
+		},
+		{
+			"preserve-outside",
+			SymlinksPreserve,
+			map[string]string{"passwd": "../../etc/passwd"},
+			"cannot point outside of the output directory",
+			"",
+			"",
+			"",
+		},
+		{
+			"preserve-absolute",
+			SymlinksPreserve,
+			map[string]string{"passwd": "/etc/passwd"},
+			"cannot point outside of the output directory",
+			"",
+			"",
+			"",
+		},
+		{
+			"follow",
+			SymlinksFollow,
+			map[string]string{"README.md": "docs/README.md"},
+			"",
+			"",
+			"",
+			"README.md",
+		},
+		{
+			"follow-outside",
+			SymlinksFollow,
+			map[string]string{"go.mod": "../../../../go.mod"},
+			"cannot be followed outside of the template",
+			"",
+			"",
+			"",
+		},
+		{
+			"follow-dir",
+			SymlinksFollow,
+			map[string]string{"manual": "docs"},
+			"is to a directory",
+			"",
+			"",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := makeLinks(t, "symlinks-"+tt.name, tt.links)
+			outDir := tmpDir + PS + "symlinks-" + tt.name + "-out"
+			tm := &TmplManifest{Symlinks: tt.symlinks, Version: SchemaVersion}
+
+			err := Print(fixture, outDir, map[string]string{"name": "tmplpress"}, tm, nil)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Print() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Print() error = %v", err)
+			}
+
+			if tt.wantLink != "" {
+				got, e := os.Readlink(outDir + PS + tt.wantLink)
+				if e != nil {
+					t.Fatalf("%v is not a link: %v", tt.wantLink, e)
+				}
+				if got != tt.wantTarget {
+					t.Errorf("link %v = %v, want %v", tt.wantLink, got, tt.wantTarget)
+				}
+			}
+
+			if tt.wantFile != "" {
+				info, e := os.Lstat(outDir + PS + tt.wantFile)
+				if e != nil {
+					t.Fatal(e)
+				}
+				if !info.Mode().IsRegular() {
+					t.Errorf("%v is not a file, mode %v", tt.wantFile, info.Mode())
+				}
+
+				content, _ := os.ReadFile(outDir + PS + tt.wantFile)
+				if string(content) != "# tmplpress\n" {
+					t.Errorf("%v = %q, want it pressed", tt.wantFile, content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+---tmplpress
+{
+    "path": "cmd/{{.appName}}/main.go",
+    "mode": "0750"
+}
+---
+package main // {{.appName}}
+
+func main() {}
@@ -0,0 +1,2 @@
+{{ template "licenseHeader" . }}
+package {{ .appName }}
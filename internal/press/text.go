@@ -0,0 +1,256 @@
+package press
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Byte order mark policies of the output of a file.
+const (
+	BOMAdd      = "add"
+	BOMPreserve = "preserve"
+	BOMRemove   = "remove"
+)
+
+// Line ending policies of the output of a file.
+const (
+	LineEndingsCRLF     = "crlf"
+	LineEndingsLF       = "lf"
+	LineEndingsPreserve = "preserve"
+)
+
+// boms Byte order marks, and the canonical name of the encoding each means,
+// a file that starts with one is in that encoding no matter the one declared.
+var boms = []struct {
+	bom      []byte
+	encoding string
+}{
+	{[]byte("\xef\xbb\xbf"), "utf-8"},
+	{[]byte("\xff\xfe"), "utf-16le"},
+	{[]byte("\xfe\xff"), "utf-16be"},
+}
+
+// TextFormat How the text of the files that match the patterns is read and
+// output, or of all other files when there are none. Files are rendered as
+// UTF-8, then output in the encoding they were read in, unless another is
+// set for the output.
+type TextFormat struct {
+	// BOM Whether the output starts with the byte order mark of its encoding,
+	// see BOMAdd, BOMRemove, and BOMPreserve, the default, which outputs one
+	// when the file has one.
+	BOM string `json:"bom,omitempty"`
+
+	// Encoding Of the files in the template, such as "utf-16le" or
+	// "windows-1252", UTF-8 when empty.
+	Encoding string   `json:"encoding,omitempty"`
+	Files    []string `json:"files,omitempty"`
+
+	// LineEndings Of the output, see LineEndingsLF, LineEndingsCRLF, and
+	// LineEndingsPreserve, the default.
+	LineEndings string `json:"lineEndings,omitempty"`
+
+	// OutputEncoding Of the output, such as "utf-8", the encoding of the file
+	// when empty.
+	OutputEncoding string `json:"outputEncoding,omitempty"`
+}
+
+// textCodec How the text of a file is decoded to be rendered, and encoded
+// to be output.
+type textCodec struct {
+	bom         []byte            // of the output encoding, output first.
+	encoding    encoding.Encoding // of the output, nil for UTF-8.
+	lineEndings string
+}
+
+// TextFor The text format of a file. The first entry with patterns that
+// match the file is used, then the entry without any patterns. Nil when
+// there is neither.
+func (tm *TmplManifest) TextFor(relativePath string) *TextFormat {
+	var global *TextFormat
+
+	for _, tf := range tm.Text {
+		if len(tf.Files) == 0 {
+			if global == nil {
+				global = tf
+			}
+			continue
+		}
+
+		if InSkipArray(relativePath, tf.Files) {
+			return tf
+		}
+	}
+
+	return global
+}
+
+// DecodeText The content of a file of the template as UTF-8, without a byte
+// order mark, as it is rendered.
+func (tm *TmplManifest) DecodeText(relativePath string, content []byte) ([]byte, error) {
+	text, _, e := tm.readText(relativePath, content)
+
+	return text, e
+}
+
+// readText Decode the content of a file to UTF-8, returning how to encode it
+// once rendered. A byte order mark is never part of the text, the output gets
+// the one of its own encoding, by the BOM policy.
+func (tm *TmplManifest) readText(relativePath string, content []byte) ([]byte, *textCodec, error) {
+	tf := tm.TextFor(relativePath)
+	if tf == nil {
+		tf = &TextFormat{}
+	}
+
+	codec := &textCodec{lineEndings: tf.LineEndings}
+	encodingIn := tf.Encoding
+	hasBOM := false
+
+	for _, b := range boms {
+		if bytes.HasPrefix(content, b.bom) {
+			encodingIn, hasBOM = b.encoding, true
+			content = content[len(b.bom):]
+			break
+		}
+	}
+
+	decoder, nameIn, e1 := lookupEncoding(encodingIn)
+	if e1 != nil {
+		return nil, nil, e1
+	}
+
+	encoder, nameOut := decoder, nameIn
+	if tf.OutputEncoding != "" {
+		var e2 error
+		if encoder, nameOut, e2 = lookupEncoding(tf.OutputEncoding); e2 != nil {
+			return nil, nil, e2
+		}
+	}
+	codec.encoding = encoder
+
+	if tf.BOM == BOMAdd || hasBOM && tf.BOM != BOMRemove {
+		codec.bom = bomFor(nameOut)
+	}
+
+	if decoder == nil {
+		return content, codec, nil
+	}
+
+	text, e3 := decoder.NewDecoder().Bytes(content)
+	if e3 != nil {
+		return nil, nil, fmt.Errorf(msg.Stderr.TextDecode, relativePath, e3.Error())
+	}
+
+	return text, codec, nil
+}
+
+// encode Rendered text with the line endings and encoding of the output.
+func (c *textCodec) encode(relativePath string, text []byte) ([]byte, error) {
+	if c == nil {
+		return text, nil
+	}
+
+	switch c.lineEndings {
+	case LineEndingsLF:
+		text = bytes.ReplaceAll(text, []byte("\r\n"), []byte("\n"))
+	case LineEndingsCRLF:
+		text = bytes.ReplaceAll(bytes.ReplaceAll(text, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n"))
+	}
+
+	if c.encoding != nil {
+		encoded, e := c.encoding.NewEncoder().Bytes(text)
+		if e != nil {
+			return nil, fmt.Errorf(msg.Stderr.TextEncode, relativePath, e.Error())
+		}
+		text = encoded
+	}
+
+	if len(c.bom) > 0 {
+		text = append(append([]byte{}, c.bom...), text...)
+	}
+
+	return text, nil
+}
+
+// checkText Verify there is only one entry for all files, and each encoding
+// is known.
+func checkText(formats []*TextFormat) error {
+	global := 0
+
+	for _, tf := range formats {
+		if len(tf.Files) == 0 {
+			global++
+		}
+
+		_, nameIn, e1 := lookupEncoding(tf.Encoding)
+		if e1 != nil {
+			return e1
+		}
+
+		nameOut := nameIn
+		if tf.OutputEncoding != "" {
+			_, name, e2 := lookupEncoding(tf.OutputEncoding)
+			if e2 != nil {
+				return e2
+			}
+			nameOut = name
+		}
+
+		switch tf.LineEndings {
+		case "", LineEndingsCRLF, LineEndingsLF, LineEndingsPreserve:
+		default:
+			return fmt.Errorf(msg.Stderr.TextLineEndings, tf.LineEndings)
+		}
+
+		switch tf.BOM {
+		case "", BOMPreserve, BOMRemove:
+		case BOMAdd:
+			if bomFor(nameOut) == nil {
+				return fmt.Errorf(msg.Stderr.TextNoBOM, nameOut)
+			}
+		default:
+			return fmt.Errorf(msg.Stderr.TextBOM, tf.BOM)
+		}
+	}
+
+	if global > 1 {
+		return fmt.Errorf(msg.Stderr.TextGlobal, global)
+	}
+
+	return nil
+}
+
+// lookupEncoding The encoding with the name, such as "utf-16le", and its
+// canonical name. The encoding is nil for UTF-8, the default, which needs no
+// decoding.
+func lookupEncoding(name string) (encoding.Encoding, string, error) {
+	if name == "" {
+		return nil, "utf-8", nil
+	}
+
+	enc, e := htmlindex.Get(name)
+	if e != nil {
+		return nil, "", fmt.Errorf(msg.Stderr.TextEncoding, name)
+	}
+
+	canonical, _ := htmlindex.Name(enc)
+	if canonical == "utf-8" {
+		return nil, canonical, nil
+	}
+
+	return enc, canonical, nil
+}
+
+// bomFor The byte order mark of an encoding, by its canonical name, nil for
+// one that has none.
+func bomFor(name string) []byte {
+	for _, b := range boms {
+		if b.encoding == name {
+			return b.bom
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package press
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTmplManifest_TextFor(t *testing.T) {
+	bat := &TextFormat{Files: []string{"*.bat"}, LineEndings: LineEndingsCRLF}
+	global := &TextFormat{LineEndings: LineEndingsLF}
+	tm := &TmplManifest{Text: []*TextFormat{global, bat}}
+
+	tests := []struct {
+		name string
+		tm   *TmplManifest
+		path string
+		want *TextFormat
+	}{
+		{"match", tm, "build.bat", bat},
+		{"global", tm, "README.md", global},
+		{"none", &TmplManifest{Text: []*TextFormat{bat}}, "README.md", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tm.TextFor(tt.path); got != tt.want {
+				t.Errorf("TextFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkText(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []*TextFormat
+		wantErr bool
+	}{
+		{"valid", []*TextFormat{{Encoding: "utf-16le", Files: []string{"*.txt"}}, {LineEndings: LineEndingsLF}}, false},
+		{"label", []*TextFormat{{Encoding: "latin1"}}, false},
+		{"unknown-encoding", []*TextFormat{{Encoding: "klingon"}}, true},
+		{"unknown-line-endings", []*TextFormat{{LineEndings: "cr"}}, true},
+		{"two-global", []*TextFormat{{LineEndings: LineEndingsLF}, {LineEndings: LineEndingsCRLF}}, true},
+		{"to-utf-8", []*TextFormat{{BOM: BOMRemove, Encoding: "utf-16le", OutputEncoding: "utf-8"}}, false},
+		{"unknown-output-encoding", []*TextFormat{{OutputEncoding: "klingon"}}, true},
+		{"unknown-bom", []*TextFormat{{BOM: "strip"}}, true},
+		{"add-bom", []*TextFormat{{BOM: BOMAdd, OutputEncoding: "utf-16be"}}, false},
+		{"add-bom-none", []*TextFormat{{BOM: BOMAdd, Encoding: "windows-1252"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkText(tt.formats); (err != nil) != tt.wantErr {
+				t.Errorf("checkText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrintText(t *testing.T) {
+	fixture := tmpDir + PS + "text-01"
+	outDir := tmpDir + PS + "text-01-out"
+	files := map[string]string{
+		"bom.md":       "\xef\xbb\xbf---tmplpress\n{\"path\": \"with-bom.md\"}\n---\n# {{.name}}\r\n",
+		"build.bat":    "echo {{.name}}\nexit\r\n",
+		"latin1.ini":   "caf\xe9={{.name}}\n",
+		"mixed.txt":    "a\r\nb\n{{.name}}\r\n",
+		"notes.utf16":  "\xff\xfen\x00:\x00 \x00{\x00{\x00.\x00n\x00a\x00m\x00e\x00}\x00}\x00\r\x00\n\x00",
+		"preserve.txt": "a\r\nb\n",
+		"add.txt":      "{{.name}}\n",
+		"strip.md":     "\xef\xbb\xbf# {{.name}}\n",
+		"bom.u16":      "\xff\xfeh\x00i\x00 \x00{\x00{\x00.\x00n\x00a\x00m\x00e\x00}\x00}\x00\n\x00",
+		"bom.u8":       "\xff\xfeh\x00i\x00 \x00{\x00{\x00.\x00n\x00a\x00m\x00e\x00}\x00}\x00\n\x00",
+		"bom-kept.u8":  "\xff\xfeh\x00i\x00\n\x00",
+	}
+
+	if e := os.MkdirAll(fixture, dirMode); e != nil {
+		t.Fatal(e)
+	}
+	for name, content := range files {
+		if e := os.WriteFile(fixture+PS+name, []byte(content), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	tm := &TmplManifest{
+		Text: []*TextFormat{
+			{Files: []string{"*.bat"}, LineEndings: LineEndingsCRLF},
+			{Encoding: "windows-1252", Files: []string{"*.ini"}},
+			{Encoding: "utf-16le", Files: []string{"*.utf16"}},
+			{Files: []string{"preserve.txt"}, LineEndings: LineEndingsPreserve},
+			{BOM: BOMAdd, Files: []string{"add.txt"}},
+			{BOM: BOMRemove, Files: []string{"strip.md", "bom.u8"}, OutputEncoding: "utf-8"},
+			{Files: []string{"bom-kept.u8"}, OutputEncoding: "utf-8"},
+			{LineEndings: LineEndingsLF},
+		},
+		Version: SchemaVersion,
+	}
+
+	if e := Print(fixture, outDir, map[string]string{"name": "café"}, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"bom-kept", "with-bom.md", "\xef\xbb\xbf# café\n"},
+		{"crlf", "build.bat", "echo café\r\nexit\r\n"},
+		{"encoding", "latin1.ini", "caf\xe9=caf\xe9\n"},
+		{"lf", "mixed.txt", "a\nb\ncafé\n"},
+		{"utf-16", "notes.utf16", "\xff\xfen\x00:\x00 \x00c\x00a\x00f\x00\xe9\x00\r\x00\n\x00"},
+		{"preserve", "preserve.txt", "a\r\nb\n"},
+		{"bom-added", "add.txt", "\xef\xbb\xbfcafé\n"},
+		{"bom-removed", "strip.md", "# café\n"},
+		{"utf-16-bom-round-trip", "bom.u16", "\xff\xfeh\x00i\x00 \x00c\x00a\x00f\x00\xe9\x00\n\x00"},
+		{"utf-16-bom-to-utf-8", "bom.u8", "hi café\n"},
+		{"bom-of-output-encoding", "bom-kept.u8", "\xef\xbb\xbfhi\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, e := os.ReadFile(outDir + PS + tt.file)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintTextCannotEncode(t *testing.T) {
+	fixture := tmpDir + PS + "text-02"
+	if e := os.MkdirAll(fixture, dirMode); e != nil {
+		t.Fatal(e)
+	}
+	if e := os.WriteFile(fixture+PS+"latin1.ini", []byte("name={{.name}}\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+
+	tm := &TmplManifest{Text: []*TextFormat{{Encoding: "windows-1252"}}, Version: SchemaVersion}
+
+	err := Print(fixture, tmpDir+PS+"text-02-out", map[string]string{"name": "日本"}, tm, nil)
+	if err == nil || !strings.Contains(err.Error(), "could not encode the output of latin1.ini") {
+		t.Errorf("Print() error = %v, want it cannot be encoded", err)
+	}
+}
@@ -0,0 +1,87 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"sort"
+	"strconv"
+)
+
+// Types of placeholders that can be inferred from how a template uses them.
+const (
+	TypeBool = "bool"
+	TypeInt  = "int"
+	TypeList = "list"
+)
+
+// typeRules The validation rule, and its message, that checks an answer is of
+// a type. Lists have no rule, since answers are strings.
+var typeRules = map[string]*validator{
+	TypeBool: {Rule: "bool", Message: "must be true or false"},
+	TypeInt:  {Rule: "int", Message: "must be a whole number"},
+}
+
+// SuggestValidation Suggest a validator for each type of placeholder that has
+// a rule, leaving out placeholders that already have validation.
+func SuggestValidation(tm *TmplManifest) {
+	fields := make(map[string][]string)
+
+	for name, typ := range tm.Types {
+		if _, ok := typeRules[typ]; !ok {
+			continue
+		}
+
+		if _, validated := findValidator(name, tm.Validation); validated {
+			continue
+		}
+
+		fields[typ] = append(fields[typ], name)
+	}
+
+	var types []string
+	for typ := range fields {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+
+	tm.SuggestedValidation = nil
+	for _, typ := range types {
+		sort.Strings(fields[typ])
+		tm.SuggestedValidation = append(tm.SuggestedValidation, &validator{
+			Fields:  fields[typ],
+			Message: typeRules[typ].Message,
+			Rule:    typeRules[typ].Rule,
+		})
+	}
+}
+
+// typedValue Convert the answer of a placeholder to its type in the manifest,
+// so "false" is false in a template. An empty answer is the zero value of the
+// type, and a placeholder with no type keeps its answer as a string.
+func typedValue(name, typ, value string) (interface{}, error) {
+	var v interface{}
+	var e error
+
+	switch typ {
+	case TypeBool:
+		if value == "" {
+			return false, nil
+		}
+		v, e = strconv.ParseBool(value)
+	case TypeInt:
+		if value == "" {
+			return 0, nil
+		}
+		v, e = strconv.Atoi(value)
+	case TypeList:
+		v, e = ListItems(value)
+	default:
+		return value, nil
+	}
+
+	if e != nil {
+		return nil, fmt.Errorf(msg.Stderr.TypedValue, name, typ, value, e.Error())
+	}
+
+	return v, nil
+}
@@ -0,0 +1,65 @@
+package press
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestValidation(t *testing.T) {
+	tm := &TmplManifest{
+		Types: map[string]string{
+			"debug":    TypeBool,
+			"ports":    TypeList,
+			"port":     TypeInt,
+			"replicas": TypeInt,
+			"docker":   TypeBool,
+		},
+		Validation: []*validator{
+			{Fields: []string{"docker"}, Rule: "bool"},
+		},
+	}
+
+	want := []*validator{
+		{Fields: []string{"debug"}, Message: typeRules[TypeBool].Message, Rule: "bool"},
+		{Fields: []string{"port", "replicas"}, Message: typeRules[TypeInt].Message, Rule: "int"},
+	}
+
+	SuggestValidation(tm)
+
+	if !reflect.DeepEqual(tm.SuggestedValidation, want) {
+		t.Errorf("got %+v, want %+v", tm.SuggestedValidation, want)
+	}
+}
+
+func TestTypedValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		value   string
+		want    interface{}
+		wantErr bool
+	}{
+		{"bool", TypeBool, "false", false, false},
+		{"bool-empty", TypeBool, "", false, false},
+		{"bool-bad", TypeBool, "nope", nil, true},
+		{"int", TypeInt, "8080", 8080, false},
+		{"int-empty", TypeInt, "", 0, false},
+		{"int-bad", TypeInt, "80a", nil, true},
+		{"list", TypeList, "a, b", []interface{}{"a", "b"}, false},
+		{"list-empty", TypeList, "", []interface{}(nil), false},
+		{"string", "", "false", "false", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := typedValue("x", tt.typ, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("typedValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("typedValue() got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/kohirens/stdlib/fsio"
 	"github.com/kohirens/stdlib/log"
 	"github.com/kohirens/tmplpress/internal/msg"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -23,7 +24,23 @@ func ValidateManifest(aFile string) error {
 	// check for existing template manifest and load it
 	tm, e1 := ReadTemplateJson(aFile)
 	if e1 != nil {
-		return fmt.Errorf(msg.Stderr.CannotReadFile, aFile, e1.Error())
+		return fmt.Errorf(msg.Stderr.ManifestDecode, aFile, e1.Error())
+	}
+
+	content, e2 := os.ReadFile(aFile)
+	if e2 != nil {
+		return fmt.Errorf(msg.Stderr.CannotReadFile, aFile, e2.Error())
+	}
+
+	// Catch unknown properties and values of the wrong type, which the JSON
+	// decoder quietly ignores.
+	errs, e3 := ValidateManifestSchema(content)
+	if e3 != nil {
+		return fmt.Errorf(msg.Stderr.SchemaCheck, aFile, e3.Error())
+	}
+
+	if e := SchemaErrors(aFile, errs); e != nil {
+		return e
 	}
 
 	if e := checkCopyAsIs(tm.CopyAsIs); e != nil {
@@ -40,6 +57,10 @@ func ValidateManifest(aFile string) error {
 		return fmt.Errorf(msg.Stderr.PlaceholdersProperty, aFile, e.Error())
 	}
 
+	if e := checkFilePatterns(tm.Render); e != nil {
+		return e
+	}
+
 	if e := checkFilePatterns(tm.Skip); e != nil {
 		return fmt.Errorf(msg.Stderr.CannotReadFile, aFile, e.Error())
 	}
@@ -48,6 +69,34 @@ func ValidateManifest(aFile string) error {
 		return fmt.Errorf(msg.Stderr.CannotReadFile, aFile, e.Error())
 	}
 
+	if e := checkPartials(aFile, tm.Partials); e != nil {
+		return fmt.Errorf(msg.Stderr.CannotReadFile, aFile, e.Error())
+	}
+
+	if e := checkData(aFile, tm.Data); e != nil {
+		return fmt.Errorf(msg.Stderr.ManifestValidation, aFile, e.Error())
+	}
+
+	if e := checkDefaults(tm.Placeholders, tm.Defaults); e != nil {
+		return fmt.Errorf(msg.Stderr.ManifestValidation, aFile, e.Error())
+	}
+
+	if e := checkDelimiters(tm.Delimiters); e != nil {
+		return fmt.Errorf(msg.Stderr.ManifestValidation, aFile, e.Error())
+	}
+
+	if e := checkModes(tm.Modes); e != nil {
+		return fmt.Errorf(msg.Stderr.ManifestValidation, aFile, e.Error())
+	}
+
+	if e := checkText(tm.Text); e != nil {
+		return fmt.Errorf(msg.Stderr.ManifestValidation, aFile, e.Error())
+	}
+
+	if e := checkForEach(tm.Placeholders, tm.ForEach); e != nil {
+		return fmt.Errorf(msg.Stderr.ManifestValidation, aFile, e.Error())
+	}
+
 	if e := checkValidationRules(tm.Placeholders, tm.Validation); e != nil {
 		return fmt.Errorf(msg.Stderr.ManifestValidation, aFile, e.Error())
 	}
@@ -101,6 +150,21 @@ func checkSubstitute(filename, dir string) error {
 	return nil
 }
 
+// checkPartials Verify the partials directory exists.
+func checkPartials(filename, dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	partialsDir := filepath.Dir(filename) + PS + dir
+
+	if !fsio.Exist(partialsDir) {
+		return fmt.Errorf(msg.Stderr.NoPartialsDir, partialsDir)
+	}
+
+	return nil
+}
+
 // checkValidationRules Verify rules apply and are of some correctness.
 // 1. Each rule maps to existing placeholders.
 // 2. Each regex rule will compile.
@@ -139,6 +203,10 @@ func checkVarName(vars map[string]string) error {
 		if !re.MatchString(name) {
 			return fmt.Errorf(msg.Stderr.InvalidPlaceholderName, name)
 		}
+
+		if IsReservedName(name) {
+			return fmt.Errorf(msg.Stderr.ReservedName, name)
+		}
 	}
 	return nil
 }
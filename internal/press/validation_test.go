@@ -533,6 +533,16 @@ func Test_checkVarName(t *testing.T) {
 			map[string]string{"a": ""},
 			true,
 		},
+		{
+			"reserved",
+			map[string]string{"Data": ""},
+			true,
+		},
+		{
+			"reserved-metadata",
+			map[string]string{"Press": ""},
+			true,
+		},
 	}
 
 	for _, tt := range tests {
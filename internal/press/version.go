@@ -0,0 +1,182 @@
+package press
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kohirens/stdlib/log"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"golang.org/x/mod/semver"
+	"os"
+	"strings"
+)
+
+// manifestDecoder Decode the content of a template manifest into the current
+// shape of a TmplManifest.
+type manifestDecoder func(content []byte) (*TmplManifest, error)
+
+// manifestDecoders Decoders for each version of the manifest still supported,
+// newest first. A manifest is decoded by the first entry whose "since" version
+// is less than or equal to its own. Versions older than the current major are
+// routed through compatibility decoders that map their properties onto the
+// current ones.
+var manifestDecoders = []struct {
+	since  string
+	decode manifestDecoder
+}{
+	{"v2.0.0", decodeManifest},
+	{"v0.0.0", decodeManifestV1},
+}
+
+// tmplManifestV1 The shape of a manifest before version 2.0.0.
+type tmplManifestV1 struct {
+	EmptyDirFile string            `json:"emptyDirFile"`
+	Excludes     []string          `json:"excludes"`
+	Placeholders map[string]string `json:"placeholders"`
+	Replace      *struct {
+		Directory string   `json:"directory"`
+		Files     []string `json:"files"`
+	} `json:"replace"`
+	Skip       []string `json:"skip"`
+	Validation []*struct {
+		Fields  []string `json:"fields"`
+		Message string   `json:"message"`
+		Pattern string   `json:"pattern"`
+		Rule    string   `json:"rule"`
+	} `json:"validation"`
+	Version string `json:"version"`
+}
+
+// CheckPressVersion Verify this build of tmplpress is at least the minimum
+// version a template requires. Only a release build knows its version, from
+// the info.go it generates (see the go:generate in main.go); for any other
+// build the check cannot be made, so a warning is given on stderr instead.
+func CheckPressVersion(tm *TmplManifest, appVersion string) error {
+	if tm.MinPressVersion == "" {
+		return nil
+	}
+
+	required, ok := canonicalVersion(tm.MinPressVersion)
+	if !ok {
+		return fmt.Errorf(msg.Stderr.InvalidMinPressVersion, tm.MinPressVersion)
+	}
+
+	current, ok2 := canonicalVersion(appVersion)
+	if !ok2 {
+		fmt.Fprintf(os.Stderr, msg.Stderr.UnknownPressVersion+"\n", tm.MinPressVersion)
+		return nil
+	}
+
+	if semver.Compare(current, required) < 0 {
+		return fmt.Errorf(msg.Stderr.PressVersionTooOld, tm.MinPressVersion, appVersion, upgradeHint)
+	}
+
+	return nil
+}
+
+// IsCurrentManifestVersion Indicates the version is read by the decoder for the
+// current schema, as opposed to a compatibility decoder.
+func IsCurrentManifestVersion(version string) bool {
+	v, ok := canonicalVersion(version)
+	if !ok {
+		return false
+	}
+
+	return semver.Major(v) == semver.Major("v"+SchemaVersion)
+}
+
+// schemaMajor The major version of the schema, such as "2", which is all of
+// the version of a manifest that IsCurrentManifestVersion compares.
+func schemaMajor() string {
+	return strings.TrimPrefix(semver.Major("v"+SchemaVersion), "v")
+}
+
+// canonicalVersion Convert a version, such as "1.2" or "v2.2.0", to the form
+// used by the semver package.
+func canonicalVersion(version string) (string, bool) {
+	v := version
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+
+	if !semver.IsValid(v) {
+		return "", false
+	}
+
+	return semver.Canonical(v), true
+}
+
+// decodeManifest Decode a manifest of the current major version.
+func decodeManifest(content []byte) (*TmplManifest, error) {
+	tmf := &TmplManifest{}
+	if e := json.Unmarshal(content, &tmf); e != nil {
+		return nil, fmt.Errorf(msg.Stderr.NewManifest, e.Error())
+	}
+
+	return tmf, nil
+}
+
+// decodeManifestV1 Decode a manifest from before version 2.0.0, where
+// "excludes" was the name of "copyAsIs", a "replace" object held the
+// substitute directory, and regular expression validators used "pattern".
+func decodeManifestV1(content []byte) (*TmplManifest, error) {
+	old := &tmplManifestV1{}
+	if e := json.Unmarshal(content, &old); e != nil {
+		return nil, fmt.Errorf(msg.Stderr.NewManifest, e.Error())
+	}
+
+	tmf := &TmplManifest{
+		CopyAsIs:     old.Excludes,
+		EmptyDirFile: old.EmptyDirFile,
+		Placeholders: old.Placeholders,
+		Skip:         old.Skip,
+		Version:      old.Version,
+	}
+
+	if old.Replace != nil {
+		tmf.Substitute = old.Replace.Directory
+		if len(old.Replace.Files) > 0 {
+			log.Warnf(msg.Stdout.ReplaceFilesIgnored, old.Replace.Files)
+		}
+	}
+
+	for _, v := range old.Validation {
+		tmf.Validation = append(tmf.Validation, &validator{
+			Expression: v.Pattern,
+			Fields:     v.Fields,
+			Message:    v.Message,
+			Rule:       v.Rule,
+		})
+	}
+
+	return tmf, nil
+}
+
+// selectDecoder Pick the decoder for a manifest version, rejecting versions
+// newer than this program supports.
+func selectDecoder(version string) (manifestDecoder, error) {
+	if version == "" {
+		return nil, fmt.Errorf(msg.Stderr.MissingTmplJsonVersion)
+	}
+
+	v, ok := canonicalVersion(version)
+	if !ok {
+		return nil, fmt.Errorf(msg.Stderr.InvalidManifestVersion, version, SchemaVersion)
+	}
+
+	// A newer patch only fixes mistakes in the schema, but a newer minor or
+	// major may add properties this program would ignore.
+	if semver.Compare(semver.MajorMinor(v), semver.MajorMinor("v"+SchemaVersion)) > 0 {
+		return nil, fmt.Errorf(msg.Stderr.ManifestTooNew, version, SchemaVersion, upgradeHint)
+	}
+
+	for _, d := range manifestDecoders {
+		if semver.Compare(v, d.since) >= 0 {
+			if d.since != manifestDecoders[0].since {
+				log.Infof(msg.Stdout.LegacyManifest, version, manifestDecoders[0].since)
+			}
+			return d.decode, nil
+		}
+	}
+
+	return nil, fmt.Errorf(msg.Stderr.InvalidManifestVersion, version, SchemaVersion)
+}
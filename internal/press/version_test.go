@@ -0,0 +1,105 @@
+package press
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewTmplManifestVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *TmplManifest
+		wantErr bool
+	}{
+		{
+			"current",
+			`{"version": "2.2.0", "copyAsIs": ["*.png"], "substitute": "replace"}`,
+			&TmplManifest{CopyAsIs: []string{"*.png"}, Substitute: "replace", Version: "2.2.0"},
+			false,
+		},
+		{
+			"legacy-1.2",
+			`{
+				"version": "1.2",
+				"placeholders": {"codeName": "name"},
+				"excludes": ["*.png"],
+				"replace": {"directory": "replace", "files": ["a:b"]},
+				"validation": [{"rule": "regExp", "fields": ["codeName"], "pattern": "^[a-z]$"}]
+			}`,
+			&TmplManifest{
+				CopyAsIs:     []string{"*.png"},
+				Placeholders: map[string]string{"codeName": "name"},
+				Substitute:   "replace",
+				Validation:   []*validator{{Expression: "^[a-z]$", Fields: []string{"codeName"}, Rule: "regExp"}},
+				Version:      "1.2",
+			},
+			false,
+		},
+		{"newer-patch", `{"version": "2.15.9"}`, &TmplManifest{Version: "2.15.9"}, false},
+		{"newer-minor", `{"version": "2.16.0"}`, nil, true},
+		{"newer-major", `{"version": "3.0.0"}`, nil, true},
+		{"missing", `{"placeholders": {}}`, nil, true},
+		{"invalid", `{"version": "two"}`, nil, true},
+		{"reserved-data", `{"version": "2.15.0", "placeholders": {"Data": "data"}}`, nil, true},
+		{"reserved-press", `{"version": "1.2", "placeholders": {"Press": "press"}}`, nil, true},
+		{"reserved-item", `{"version": "2.15.0", "placeholders": {"envs": "envs"}, "forEach": [{"as": "Data", "files": ["a"], "list": "envs", "path": "a"}]}`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTmplManifest([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTmplManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewTmplManifest() got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPressVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		minVersion string
+		appVersion string
+		wantErr    bool
+	}{
+		{"not-set", "", "4.0.0", false},
+		{"dev-build", "4.1.0", "", false},
+		{"same", "4.1.0", "4.1.0", false},
+		{"newer", "4.1", "v4.2.0", false},
+		{"older", "4.1.0", "4.0.3", true},
+		{"invalid", "latest", "4.0.3", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TmplManifest{MinPressVersion: tt.minVersion, Version: SchemaVersion}
+			if err := CheckPressVersion(tm, tt.appVersion); (err != nil) != tt.wantErr {
+				t.Errorf("CheckPressVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSchemaVersion Keep the version of the schema in sync with the version of
+// the manifest this program supports.
+func TestSchemaVersion(t *testing.T) {
+	v := struct {
+		Version string `json:"version"`
+	}{}
+	if e := decodeJson(tmplSchema, &v); e != nil {
+		t.Fatal(e)
+	}
+
+	if v.Version != SchemaVersion {
+		t.Errorf("template.schema.json version %v, want %v", v.Version, SchemaVersion)
+	}
+	if got := schemaMajor(); !strings.HasPrefix(SchemaVersion, got+".") {
+		t.Errorf("schemaMajor() = %q, want the major version of %v", got, SchemaVersion)
+	}
+}
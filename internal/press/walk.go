@@ -0,0 +1,127 @@
+package press
+
+import (
+	"fmt"
+	"github.com/kohirens/stdlib/fsio"
+	"github.com/kohirens/stdlib/log"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WalkFunc Called with each file of a template, the relative path is from the
+// root of the template, with the separator of the OS.
+type WalkFunc func(sourcePath, relativePath string, d fs.DirEntry) error
+
+// Walker Walks the files of a template that are pressed, in lexical order.
+// Directories that are never pressed are not walked at all: the git
+// directory, the substitute directory, and those a pattern of skip, or of the
+// ignore file, matches. The template manifest, ignore file, partials, and
+// data files are not walked either. Press and the manifest subcommand both
+// walk a template with it, so they agree on which files are in it.
+type Walker struct {
+	// Skipped When set, is called with each file and directory that is not
+	// walked because of a pattern of skip, or of the ignore file.
+	Skipped func(relativePath, pattern string)
+
+	// Substitute Walk the files of the substitute directory too. Patterns
+	// are matched against the path each is pressed to, the root of the
+	// template.
+	Substitute bool
+
+	Tm *TmplManifest
+}
+
+// Walk the files of a template in the directory.
+func (w *Walker) Walk(tplDir string, fn WalkFunc) error {
+	normTplDir := fsio.Normalize(tplDir)
+	substitute := ""
+	if w.Tm.Substitute != "" {
+		substitute = filepath.Clean(fsio.Normalize(w.Tm.Substitute))
+	}
+
+	skips, e1 := SkipPatterns(normTplDir, w.Tm)
+	if e1 != nil {
+		return e1
+	}
+
+	return filepath.WalkDir(normTplDir, func(sourcePath string, d fs.DirEntry, wErr error) error {
+		if wErr != nil {
+			return wErr
+		}
+
+		relativePath := strings.TrimLeft(strings.TrimPrefix(fsio.Normalize(sourcePath), normTplDir), "\\/")
+		if relativePath == "" {
+			return nil
+		}
+
+		if d.IsDir() {
+			if d.Name() == gitConfigDir || (!w.Substitute && relativePath == substitute) {
+				log.Infof(msg.Stdout.Skipping, sourcePath)
+				return filepath.SkipDir
+			}
+
+			if pattern, ok := skips.Match(w.pressedPath(relativePath, substitute), true); ok {
+				w.skipped(relativePath, pattern)
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		// Partials and data files are only used by the other files.
+		if d.Name() == TmplManifestFile || relativePath == IgnoreFile || w.Tm.InPartials(relativePath) || w.Tm.InData(relativePath) {
+			log.Infof(msg.Stdout.Skipping, relativePath)
+			return nil
+		}
+
+		if pattern, ok := skips.Match(w.pressedPath(relativePath, substitute), false); ok {
+			w.skipped(relativePath, pattern)
+			return nil
+		}
+
+		return fn(sourcePath, relativePath, d)
+	})
+}
+
+// pressedPath The path a file is pressed to, which for a file of the
+// substitute directory is from the root of the template.
+func (w *Walker) pressedPath(relativePath, substitute string) string {
+	if substitute == "" {
+		return relativePath
+	}
+
+	if rest, ok := strings.CutPrefix(relativePath, substitute+PS); ok {
+		return rest
+	}
+
+	return relativePath
+}
+
+func (w *Walker) skipped(relativePath, pattern string) {
+	log.Infof(msg.Stdout.Skipping, relativePath)
+
+	if w.Skipped != nil {
+		w.Skipped(relativePath, pattern)
+	}
+}
+
+// SkipPatterns The patterns of the files of a template to skip, those of the
+// manifest, then those of the ignore file in the root of the template, if
+// there is one.
+func SkipPatterns(tplDir string, tm *TmplManifest) (Patterns, error) {
+	texts := tm.Skip
+
+	filename := tplDir + PS + IgnoreFile
+	if fsio.Exist(filename) {
+		content, e := os.ReadFile(filename)
+		if e != nil {
+			return nil, fmt.Errorf(msg.Stderr.CannotReadFile, filename, e.Error())
+		}
+		texts = append(append([]string{}, tm.Skip...), ReadPatterns(content)...)
+	}
+
+	return NewPatterns(texts), nil
+}
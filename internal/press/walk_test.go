@@ -0,0 +1,95 @@
+package press
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWalker_Walk(t *testing.T) {
+	dir := tmpDir + PS + "walk-01"
+
+	for _, f := range []string{
+		".git/HEAD",
+		"a.log",
+		"a.txt",
+		"docs/keep.md",
+		"docs/skip.md",
+		"node_modules/pkg/index.js",
+		"partials/_header.tmpl",
+		"replace/a.txt",
+		"replace/skip.md",
+		"vendor/lib.txt",
+		TmplManifestFile,
+	} {
+		file := filepath.FromSlash(dir + "/" + f)
+		if e := os.MkdirAll(filepath.Dir(file), dirMode); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(file, []byte(f), 0644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	if e := os.WriteFile(dir+PS+IgnoreFile, []byte("# logs\n*.log\n"), 0644); e != nil {
+		t.Fatal(e)
+	}
+
+	tm := &TmplManifest{
+		Partials:   "partials",
+		Skip:       []string{"docs/skip.md", "node_modules/*", "vendor", "skip.md"},
+		Substitute: "replace",
+	}
+
+	tests := []struct {
+		name        string
+		substitute  bool
+		want        []string
+		wantSkipped []string
+	}{
+		{
+			"press",
+			false,
+			[]string{"a.txt", "docs/keep.md"},
+			[]string{"a.log", "docs/skip.md", "node_modules/pkg", "vendor"},
+		},
+		{
+			"with-substitute",
+			true,
+			[]string{"a.txt", "docs/keep.md", "replace/a.txt"},
+			[]string{"a.log", "docs/skip.md", "node_modules/pkg", "replace/skip.md", "vendor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, skipped []string
+
+			w := &Walker{
+				Skipped: func(relativePath, pattern string) {
+					skipped = append(skipped, filepath.ToSlash(relativePath))
+				},
+				Substitute: tt.substitute,
+				Tm:         tm,
+			}
+
+			e := w.Walk(dir, func(sourcePath, relativePath string, d fs.DirEntry) error {
+				got = append(got, filepath.ToSlash(relativePath))
+				return nil
+			})
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Walk() walked %v, want %v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("Walk() skipped %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package press
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"gopkg.in/yaml.v3"
+	"io"
+	"time"
+)
+
+// parseYaml Decode the content of a YAML data file. Values are normalized to
+// the same types as JSON, so both kinds of data files work the same in a
+// template. Only one document is allowed.
+func parseYaml(content []byte) (interface{}, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+
+	var v interface{}
+	if e := dec.Decode(&v); e != nil {
+		if errors.Is(e, io.EOF) {
+			return nil, nil
+		}
+		return nil, e
+	}
+
+	var next interface{}
+	if e := dec.Decode(&next); !errors.Is(e, io.EOF) {
+		return nil, fmt.Errorf(msg.Stderr.YamlDocuments)
+	}
+
+	return jsonValue(v), nil
+}
+
+// jsonValue Convert a decoded YAML value to the types encoding/json decodes
+// to: mappings with keys that are not strings get them formatted as strings,
+// all numbers become a float64, and timestamps become a string again.
+func jsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = jsonValue(val)
+		}
+		return t
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = jsonValue(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range t {
+			t[i] = jsonValue(val)
+		}
+		return t
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	case float32:
+		return float64(t)
+	case time.Time:
+		if t.Equal(t.Truncate(24*time.Hour)) && t.Location() == time.UTC {
+			return t.Format(time.DateOnly)
+		}
+		return t.Format(time.RFC3339Nano)
+	}
+
+	return v
+}
@@ -0,0 +1,105 @@
+package press
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseYaml(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    interface{}
+		wantErr bool
+	}{
+		{"empty", "# nothing\n", nil, false},
+		{
+			"mapping",
+			"---\nname: billing # the app\nport: 8080\nenabled: true\nratio: 0.5\nnone: ~\nurl: http://example.com:80/a#b\n",
+			map[string]interface{}{
+				"name":    "billing",
+				"port":    float64(8080),
+				"enabled": true,
+				"ratio":   0.5,
+				"none":    nil,
+				"url":     "http://example.com:80/a#b",
+			},
+			false,
+		},
+		{
+			"nested",
+			"regions:\n  us-east:\n    endpoint: https://us.example.com\n  eu-west:\n    endpoint: https://eu.example.com\n",
+			map[string]interface{}{
+				"regions": map[string]interface{}{
+					"us-east": map[string]interface{}{"endpoint": "https://us.example.com"},
+					"eu-west": map[string]interface{}{"endpoint": "https://eu.example.com"},
+				},
+			},
+			false,
+		},
+		{
+			"sequence-of-mappings",
+			"teams:\n- name: payments\n  owners:\n    - ann\n    - bo\n- name: search\n  owners: []\n",
+			map[string]interface{}{
+				"teams": []interface{}{
+					map[string]interface{}{"name": "payments", "owners": []interface{}{"ann", "bo"}},
+					map[string]interface{}{"name": "search", "owners": []interface{}{}},
+				},
+			},
+			false,
+		},
+		{
+			"top-level-sequence",
+			"- a\n-\n  - b\n- 'it''s'\n",
+			[]interface{}{"a", []interface{}{"b"}, "it's"},
+			false,
+		},
+		{
+			"flow",
+			`zones: [a, "b, c", {id: 1, tags: [x]}]` + "\n",
+			map[string]interface{}{
+				"zones": []interface{}{"a", "b, c", map[string]interface{}{"id": float64(1), "tags": []interface{}{"x"}}},
+			},
+			false,
+		},
+		{
+			"quoted",
+			"\"a: b\": \"line\\nbreak\"\nc: 'd # e'\n",
+			map[string]interface{}{"a: b": "line\nbreak", "c": "d # e"},
+			false,
+		},
+		{"crlf", "a: 1\r\nb: 2\r\n", map[string]interface{}{"a": float64(1), "b": float64(2)}, false},
+		{"bad-indent", "a:\n    b: 1\n  c: 2\n", nil, true},
+		{"duplicate-key", "a: 1\na: 2\n", nil, true},
+		{"tab", "a:\n\tb: 1\n", nil, true},
+		{"documents", "a: 1\n---\nb: 2\n", nil, true},
+		{
+			"block-scalars",
+			"a: |\n  line 1\n  line 2\nb: >-\n  folded\n  text\n",
+			map[string]interface{}{"a": "line 1\nline 2\n", "b": "folded text"},
+			false,
+		},
+		{
+			"key-types",
+			"1: one\ntrue: yes\nwhen: 2024-01-02\nat: 2024-01-02T03:04:05Z\n",
+			map[string]interface{}{"1": "one", "true": "yes", "when": "2024-01-02", "at": "2024-01-02T03:04:05Z"},
+			false,
+		},
+		{"nested-plain-mapping", "a: b: c\n", nil, true},
+		{"unclosed-flow", "a: [1, 2\n", nil, true},
+		{"unclosed-quote", "a: \"b\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYaml([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseYaml() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYaml() got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -45,3 +45,22 @@ func TmpSetParentDataDir(d string) func() {
 		}
 	}
 }
+
+// Schemas Read the answers and template schemas from the root of the
+// repository, which the main package embeds, for a test to validate with.
+func Schemas() ([]byte, []byte) {
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), "..", "..")
+
+	answers, e1 := os.ReadFile(filepath.Join(root, "answers.schema.json"))
+	if e1 != nil {
+		panic(fmt.Sprintf("failed to read the answers schema for unit test: %v", e1))
+	}
+
+	tmpl, e2 := os.ReadFile(filepath.Join(root, "template.schema.json"))
+	if e2 != nil {
+		panic(fmt.Sprintf("failed to read the template schema for unit test: %v", e2))
+	}
+
+	return answers, tmpl
+}
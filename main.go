@@ -3,6 +3,8 @@ package main
 //go:generate git-tool-belt semver -save info.go -format go -packageName main -varName flags
 
 import (
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	stdc "github.com/kohirens/stdlib/cli"
@@ -13,9 +15,13 @@ import (
 	"github.com/kohirens/tmplpress/internal/press"
 	"github.com/kohirens/tmplpress/subcommand/config"
 	"github.com/kohirens/tmplpress/subcommand/manifest"
+	"github.com/kohirens/tmplpress/subcommand/templatize"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
+	"syscall"
+	"time"
 )
 
 const (
@@ -37,6 +43,8 @@ func init() {
 	// Define all flags
 	defineFlags(flags)
 
+	press.UseSchemas(answersSchema, tmplSchema)
+
 	usg := stdc.NewUsage(AppName, um, nil, Summary, usageTmpl2)
 	usg.Command.AddCommand(
 		config.Init(),
@@ -54,15 +62,30 @@ func init() {
 		manifest.Summary,
 		manifest.UsageTmpl,
 	)
+	usg.Command.AddCommand(
+		templatize.Init(),
+		templatize.Name,
+		templatize.UsageMessages,
+		templatize.UsageVars,
+		templatize.Summary,
+		templatize.UsageTmpl,
+	)
 }
 
 func main() {
 	var mainErr error
 
 	defer func() {
+		// Exiting would hide a panic, and that it failed, so let it crash.
+		if r := recover(); r != nil {
+			panic(r)
+		}
+
+		// All on stderr, to keep output such as JSON on stdout clean.
 		if mainErr != nil {
-			logf(msg.Stderr.FatalHeader)
-			log.Fatf(mainErr.Error())
+			fmt.Fprintln(os.Stderr, msg.Stderr.FatalHeader)
+			fmt.Fprintln(os.Stderr, mainErr.Error())
+			os.Exit(1)
 		}
 		os.Exit(0)
 	}()
@@ -122,6 +145,9 @@ func main() {
 		case manifest.Name:
 			mainErr = manifest.Run(ca[1:])
 			return
+		case templatize.Name:
+			mainErr = templatize.Run(ca[1:])
+			return
 		}
 	}
 
@@ -130,10 +156,23 @@ func main() {
 		return
 	}
 
-	var tmplToPress string
+	now, e1 := pressTime(flags.FixedTime)
+	if e1 != nil {
+		mainErr = e1
+		return
+	}
+
+	var user *press.UserMeta
+	if flags.FixedUser != "" {
+		if user, mainErr = press.ParseUser(flags.FixedUser); mainErr != nil {
+			return
+		}
+	}
+
+	var tmplToPress, commitHash string
 
 	if flags.TmplType == "git" {
-		var repo, commitHash string
+		var repo string
 		var err2 error
 
 		if flags.Branch == "latest" {
@@ -178,6 +217,16 @@ func main() {
 		return
 	}
 
+	if e := press.CheckManifestSchema(tmplManifestFile); e != nil {
+		mainErr = e
+		return
+	}
+
+	if e := press.CheckPressVersion(tmplJson, flags.CurrentVersion); e != nil {
+		mainErr = e
+		return
+	}
+
 	if e := press.Substitute(tmplToPress+ps+tmplJson.Substitute, tmplToPress); e != nil {
 		mainErr = e
 		return
@@ -195,14 +244,50 @@ func main() {
 	}
 
 	// Checks for any missing placeholder values waits for their input from the CLI.
-	if e := press.GetPlaceholderInput(tmplJson, appData.AnswersJson.Placeholders, os.Stdin, flags.DefaultVal); e != nil {
+	// One scanner for every prompt, as it reads ahead of the line it returns.
+	answers := bufio.NewScanner(os.Stdin)
+
+	if e := press.GetPlaceholderInput(tmplJson, appData.AnswersJson.Placeholders, answers, flags.DefaultVal, flags.OutPath); e != nil {
 		mainErr = fmt.Errorf(msg.Stderr.GettingAnswers, e.Error())
 		return
 	}
 
 	press.ShowAllPlaceholderValues(tmplJson, appData.AnswersJson.Placeholders)
 
-	mainErr = press.Print(tmplToPress, flags.OutPath, appData.AnswersJson.Placeholders, tmplJson)
+	meta := press.NewMetadata(now, flags.CurrentVersion, &press.TemplateMeta{
+		Commit: commitHash,
+		Ref:    flags.Branch,
+		Source: flags.TmplPath,
+	}, user)
+
+	// Stop pressing on Ctrl-C, so the staged output is removed.
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	mainErr = press.Print(tmplToPress, flags.OutPath, appData.AnswersJson.Placeholders, tmplJson, &press.PrintOptions{
+		Answers:       answers,
+		Conflicts:     flags.Conflicts,
+		Context:       stop,
+		DataFiles:     flags.DataFiles,
+		Jobs:          flags.Jobs,
+		MaxRenderSize: appData.MaxRenderSize,
+		Meta:          meta,
+	})
+}
+
+// pressTime The time to press the template at, which is now unless a fixed
+// time is given.
+func pressTime(fixedTime string) (time.Time, error) {
+	if fixedTime == "" {
+		return time.Now(), nil
+	}
+
+	t, e := time.Parse(time.RFC3339, fixedTime)
+	if e != nil {
+		return time.Time{}, fmt.Errorf(msg.Stderr.FixedTime, fixedTime, e.Error())
+	}
+
+	return t, nil
 }
 
 func parseMainArgs(af *appFlags, pArgs []string) error {
@@ -259,7 +344,7 @@ func validateMainArgs(af *appFlags) error {
 		return fmt.Errorf(errors.OutPathCollision, af.TmplPath, af.OutPath)
 	}
 
-	if fsio.DirExist(af.OutPath) {
+	if fsio.DirExist(af.OutPath) && len(af.Conflicts) == 0 {
 		return fmt.Errorf(stdout.OutPathExist, af.OutPath)
 	}
 
@@ -192,6 +192,9 @@ func TestSetUserOptions(tester *testing.T) {
 		want     string
 	}{
 		{"setCache", 0, []string{"-verbosity", "6", config.Name, "set", "CacheDir", "ABC123"}, "ABC123"},
+		{"setMaxRenderSize", 0, []string{config.Name, "set", "MaxRenderSize", "20000000"}, `"MaxRenderSize":20000000`},
+		{"keepOtherSettings", 0, []string{config.Name, "set", "MaxRenderSize", "30000000"}, `"CacheDir":"ABC123"`},
+		{"invalidMaxRenderSize", 1, []string{config.Name, "set", "MaxRenderSize", "big"}, `"MaxRenderSize":30000000`},
 	}
 
 	for _, tc := range tests {
@@ -521,3 +524,33 @@ func TestTemplateWithNoPlaceholders(tester *testing.T) {
 		})
 	}
 }
+
+func Test_pressTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		fixedTime string
+		wantYear  int
+		wantErr   bool
+	}{
+		{"fixed", "2024-01-02T15:04:05Z", 2024, false},
+		{"with-offset", "1999-12-31T23:00:00-05:00", 1999, false},
+		{"not-rfc-3339", "2024-01-02", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pressTime(tt.fixedTime)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pressTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got.Year() != tt.wantYear {
+				t.Errorf("pressTime() got year %v, want %v", got.Year(), tt.wantYear)
+			}
+		})
+	}
+
+	if got, _ := pressTime(""); got.IsZero() {
+		t.Errorf("pressTime() want the current time when no time is fixed")
+	}
+}
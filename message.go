@@ -19,14 +19,19 @@ var errors = struct {
 var stdout = struct {
 	OutPathExist string
 }{
-	OutPathExist: "out-path already exits %q",
+	OutPathExist: "out-path already exits %q, use -on-conflict to press into it",
 }
 
 var um = map[string]string{
 	"answer-path": "Path to a JSON file containing the values for placeholders (which are the keys) defined by a template.",
 	"branch":      "Branch of the template to clone when tmplType=git.",
+	"data":        "Path to a JSON or YAML file given to the template as .Data, named by its file name; can be repeated.",
 	"default-val": "Used for any unset placeholders and prevents the program waiting for input.",
+	"fixed-time":  "A time, such as 2024-01-02T15:04:05Z, to press the template at instead of now, so the output is the same each time.",
+	"fixed-user":  "A user, such as \"Ann <ann@example.com>\", to press the template as instead of the one in the git config, so the output is the same each time.",
 	"help":        "Prints usage information and exit 0.",
+	"jobs":        "The number of files to press at the same time, defaults to the number of CPUs.",
+	"on-conflict": "Allow the output directory to exist, with a policy for the files it already has: backup, fail, overwrite, prompt, or skip; or a pattern=policy pair for the files that match the pattern; can be repeated.",
 	"out-path":    "Path to output the new project.",
 	"tmpl-path":   "URL to a git repository or a local path to a directory.",
 	"tmpl-type":   "Can be of git.",
@@ -0,0 +1,15 @@
+package main
+
+import (
+	_ "embed"
+)
+
+// The schemas live at the root of the repository so that they can be
+// referenced by URL; they are embedded to validate input against.
+var (
+	//go:embed answers.schema.json
+	answersSchema []byte
+
+	//go:embed template.schema.json
+	tmplSchema []byte
+)
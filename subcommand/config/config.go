@@ -7,6 +7,7 @@ import (
 	"github.com/kohirens/stdlib/log"
 	"github.com/kohirens/tmplpress/internal/msg"
 	"github.com/kohirens/tmplpress/internal/press"
+	"strconv"
 )
 
 type Arguments struct {
@@ -109,6 +110,11 @@ func get(key string, cp string) error {
 		log.Logf("%v", val)
 		break
 
+	case "MaxRenderSize":
+		val = press.RenderLimit(nil, sc.MaxRenderSize)
+		log.Logf("%v", val)
+		break
+
 	default:
 		return fmt.Errorf(msg.Stderr.NoSetting, key)
 	}
@@ -120,11 +126,28 @@ func get(key string, cp string) error {
 func set(key, val string, cp, appName string) error {
 	sc := &press.ConfigSaveData{}
 
+	// Keep the other settings.
+	if fsio.Exist(cp) {
+		saved, e := press.LoadConfig(cp)
+		if e != nil {
+			return e
+		}
+		sc = saved
+	}
+
 	switch key {
 	case "CacheDir":
 		sc.CacheDir = val
 		break
 
+	case "MaxRenderSize":
+		size, e := strconv.ParseInt(val, 10, 64)
+		if e != nil || size < 1 {
+			return fmt.Errorf(Stderr.InvalidMaxRenderSize, val)
+		}
+		sc.MaxRenderSize = size
+		break
+
 	default:
 		return fmt.Errorf(msg.Stderr.NoSetting, key)
 	}
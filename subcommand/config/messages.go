@@ -7,10 +7,13 @@ Examples
 
 	config set "CacheDir" "./path/to/a/directory"
 	config get "CacheDir"
+	config set "MaxRenderSize" "20000000"
 
 Settings
 
 	CacheDir - Path to store template downloaded
+	MaxRenderSize - The largest file, in bytes, that is rendered as a template,
+	    the maxRenderSize of a template manifest takes precedence
 
 Command
 
@@ -22,10 +25,12 @@ var Stderr = struct {
 	CouldNotEncodeConfig string
 	ConfigValueNotSet    string
 	InvalidConfigMethod  string
+	InvalidMaxRenderSize string
 }{
 	CouldNotEncodeConfig: "could not JSON encode user configuration settings, %v",
 	ConfigValueNotSet:    "no value passed in for the setting, try quotes to enter an empty string",
 	InvalidConfigMethod:  "invalid config method %v",
+	InvalidMaxRenderSize: "MaxRenderSize must be a number of bytes greater than 0, got %q",
 }
 
 var UsageMessages = map[string]string{
@@ -0,0 +1,118 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffLine A line of a diff, kind is one of ' ', '-', or '+'.
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff Show the changes between two texts in the unified diff format,
+// with n lines of context around each change. Manifests are small, so a
+// simple longest common subsequence is good enough.
+func unifiedDiff(fromName, toName string, from, to []byte, n int) string {
+	a := splitLines(string(from))
+	b := splitLines(string(to))
+	lines := diffLines(a, b)
+
+	sb := &strings.Builder{}
+	sb.WriteString("--- " + fromName + "\n")
+	sb.WriteString("+++ " + toName + "\n")
+
+	// Line numbers in a and b of each diff line.
+	aNo := make([]int, len(lines)+1)
+	bNo := make([]int, len(lines)+1)
+	for i, l := range lines {
+		aNo[i+1], bNo[i+1] = aNo[i], bNo[i]
+		if l.kind != '+' {
+			aNo[i+1]++
+		}
+		if l.kind != '-' {
+			bNo[i+1]++
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		if lines[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are within 2n lines of each other.
+		start := max(0, i-n)
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].kind != ' ' {
+				end = j
+			} else if j-end > 2*n {
+				break
+			}
+		}
+		end = min(len(lines), end+n+1)
+
+		fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aNo[start]+1, aNo[end]-aNo[start], bNo[start]+1, bNo[end]-bNo[start])
+		for _, l := range lines[start:end] {
+			sb.WriteString(string(l.kind) + l.text + "\n")
+		}
+
+		i = end
+	}
+
+	return sb.String()
+}
+
+// diffLines Compute the lines removed from a and added from b.
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
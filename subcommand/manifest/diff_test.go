@@ -0,0 +1,41 @@
+package manifest
+
+import (
+	"testing"
+)
+
+func Test_unifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			"change",
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			"--- x\n+++ y\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"same",
+			"a\n",
+			"a\n",
+			"--- x\n+++ y\n",
+		},
+		{
+			"two-hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			"0\n2\n3\n4\n5\n6\n7\n8\n10\n",
+			"--- x\n+++ y\n@@ -1,2 +1,2 @@\n-1\n+0\n 2\n@@ -8,2 +8,2 @@\n 8\n-9\n+10\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("x", "y", []byte(tt.from), []byte(tt.to), 1); got != tt.want {
+				t.Errorf("unifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/kohirens/stdlib/fsio"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"github.com/kohirens/tmplpress/internal/press"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	lintError   = "error"
+	lintWarning = "warning"
+)
+
+// reParseErrLoc Pull the file:line out of an error from parsing a template.
+var reParseErrLoc = regexp.MustCompile(`^template: (.+?:\d+(?::\d+)?): (.*)$`)
+
+// lintFinding A problem found in a template, located by file:line.
+type lintFinding struct {
+	Level    string `json:"level"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (f *lintFinding) String() string {
+	return f.Location + ": " + f.Level + ": " + f.Message
+}
+
+// linter Cross-check a template manifest against the files of the template.
+type linter struct {
+	dir      string
+	content  []byte
+	tm       *press.TmplManifest
+	partials *template.Template
+	used     map[string]bool
+	matched  map[string]bool
+	reported map[string]bool
+	findings []*lintFinding
+}
+
+// lint Check the template of a manifest, writing the findings in the format
+// given. Returns an error when any of the findings are errors.
+func lint(filename, format string, w io.Writer) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf(stderr.LintFormat, format)
+	}
+
+	dir := filepath.Dir(filename)
+
+	findings, e1 := lintTemplate(dir)
+	if e1 != nil {
+		return e1
+	}
+
+	if e := printFindings(w, findings, format); e != nil {
+		return e
+	}
+
+	if n := countErrors(findings); n > 0 {
+		return fmt.Errorf(stderr.LintFailed, n, dir)
+	}
+
+	return nil
+}
+
+// lintTemplate Parse every file of a template that would be pressed, and
+// report the differences between the placeholders it uses and the ones
+// declared in its manifest.
+func lintTemplate(dir string) ([]*lintFinding, error) {
+	filename := dir + ps + press.TmplManifestFile
+
+	content, e1 := os.ReadFile(filename)
+	if e1 != nil {
+		return nil, fmt.Errorf(msg.Stderr.CannotReadFile, filename, e1.Error())
+	}
+
+	tm, e2 := press.NewTmplManifest(content)
+	if e2 != nil {
+		return nil, e2
+	}
+
+	l := &linter{
+		dir:      fsio.Normalize(dir),
+		content:  content,
+		tm:       tm,
+		used:     make(map[string]bool),
+		matched:  make(map[string]bool),
+		reported: make(map[string]bool),
+		findings: []*lintFinding{},
+	}
+
+	partials, e3 := press.LoadPartials(l.dir, tm)
+	if e3 != nil {
+		l.add(lintError, tm.Partials, "%v", e3.Error())
+	}
+	l.partials = partials
+	l.addRefs(walkPartials(partials))
+
+	// Files of the substitute directory are pressed too, over the root of
+	// the template.
+	w := &press.Walker{
+		Skipped: func(relativePath, pattern string) {
+			l.matched["skip"+pattern] = true
+		},
+		Substitute: true,
+		Tm:         tm,
+	}
+
+	if e := w.Walk(l.dir, l.lintFile); e != nil {
+		return nil, e
+	}
+
+	l.lintManifest()
+
+	return l.findings, nil
+}
+
+// lintFile Parse a file of the template, recording the placeholders it uses.
+func (l *linter) lintFile(sourcePath, relativePath string, d fs.DirEntry) error {
+	relativePath = filepath.ToSlash(relativePath)
+
+	if d.Name() == l.tm.EmptyDirFile || l.tm.PreservesLink(d) {
+		return nil
+	}
+
+	// Files in the substitute directory are copied over the root of the
+	// template before it is pressed.
+	pressedPath := relativePath
+	if l.tm.Substitute != "" {
+		pressedPath = strings.TrimPrefix(relativePath, strings.Trim(filepath.ToSlash(l.tm.Substitute), "/")+"/")
+	}
+
+	if l.match(pressedPath, "copyAsIs", l.tm.CopyAsIs) {
+		return nil
+	}
+
+	for _, fe := range l.tm.ForEach {
+		l.match(pressedPath, "forEach", fe.Files)
+	}
+
+	content, e1 := os.ReadFile(sourcePath)
+	if e1 != nil {
+		return fmt.Errorf(msg.Stderr.CannotReadFile, sourcePath, e1.Error())
+	}
+
+	rendered := l.match(pressedPath, "render", l.tm.Render)
+	if !rendered && l.tm.ChecksBinary(pressedPath) && press.IsBinary(content) {
+		l.add(lintWarning, relativePath, lintMsg.Binary)
+		return nil
+	}
+
+	text, e2 := l.tm.DecodeText(pressedPath, content)
+	if e2 != nil {
+		l.add(lintError, relativePath, "%v", e2.Error())
+		return nil
+	}
+
+	if _, _, e := press.ReadFrontMatter(text); e != nil {
+		l.add(lintError, relativePath, lintMsg.FrontMatter, e.Error())
+		return nil
+	}
+
+	refs, e3 := walkFile(relativePath, pressedPath, text, l.tm, l.partials)
+	if e3 != nil {
+		loc, message := relativePath, e3.Error()
+		if m := reParseErrLoc.FindStringSubmatch(message); m != nil {
+			loc, message = m[1], m[2]
+		}
+		l.add(lintError, loc, lintMsg.ParseFailed, message)
+		return nil
+	}
+
+	l.addRefs(refs)
+
+	return nil
+}
+
+// addRefs Record the placeholders used, reporting the first use in each file
+// of those not declared.
+func (l *linter) addRefs(refs []*fieldRef) {
+	for _, ref := range refs {
+		l.used[ref.name] = true
+
+		if _, ok := l.tm.Placeholders[ref.name]; ok {
+			continue
+		}
+
+		key := ref.tree.ParseName + ":" + ref.name
+		if l.reported[key] {
+			continue
+		}
+
+		l.reported[key] = true
+		l.add(lintError, ref.location(), lintMsg.Undeclared, ref.name)
+	}
+}
+
+// lintManifest Report anything in the manifest the template files do not use.
+func (l *linter) lintManifest() {
+	names := make([]string, 0, len(l.tm.Placeholders))
+	for name := range l.tm.Placeholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !l.used[name] {
+			l.add(lintWarning, l.location("placeholders", name), lintMsg.UnusedPlaceholder, name)
+		}
+	}
+
+	for _, pattern := range l.tm.CopyAsIs {
+		if !l.matched["copyAsIs"+pattern] {
+			l.add(lintWarning, l.location("copyAsIs", pattern), lintMsg.UnmatchedGlob, "copyAsIs", pattern)
+		}
+	}
+
+	for _, pattern := range l.tm.Render {
+		if !l.matched["render"+pattern] {
+			l.add(lintWarning, l.location("render", pattern), lintMsg.UnmatchedGlob, "render", pattern)
+		}
+	}
+
+	for _, fe := range l.tm.ForEach {
+		for _, pattern := range fe.Files {
+			if !l.matched["forEach"+pattern] {
+				l.add(lintWarning, l.location("forEach", pattern), lintMsg.UnmatchedGlob, "forEach", pattern)
+			}
+		}
+	}
+
+	for _, pattern := range l.tm.Skip {
+		if !l.matched["skip"+pattern] {
+			l.add(lintWarning, l.location("skip", pattern), lintMsg.UnmatchedGlob, "skip", pattern)
+		}
+	}
+
+	for _, pc := range press.PatternChanges(l.tm) {
+		l.add(lintWarning, l.location(pc.Property, pc.Pattern), lintMsg.PatternChanged, pc.Property, pc.Pattern)
+	}
+
+	for _, v := range l.tm.Validation {
+		for _, field := range v.Fields {
+			if !l.used[field] {
+				l.add(lintWarning, l.location("validation", field), lintMsg.UnusedValidator, v.Rule, field)
+			}
+		}
+	}
+}
+
+// match Check a file against the patterns of a property, remembering the
+// ones that matched any file. A negation is remembered when the file matches
+// it without its "!", since it then re-includes the file.
+func (l *linter) match(relativePath, property string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if press.InSkipArray(relativePath, []string{strings.TrimPrefix(pattern, "!")}) {
+			l.matched[property+pattern] = true
+		}
+	}
+
+	return press.InSkipArray(relativePath, patterns)
+}
+
+// location The manifest file and line where a value first appears after a
+// property, falling back to the line of the property.
+func (l *linter) location(property, value string) string {
+	prop := bytes.Index(l.content, []byte(`"`+property+`"`))
+	if prop < 0 {
+		return press.TmplManifestFile
+	}
+
+	offset := prop
+	quoted, _ := json.Marshal(value)
+	if i := bytes.Index(l.content[prop:], quoted); i >= 0 {
+		offset = prop + i
+	}
+
+	return fmt.Sprintf("%v:%d", press.TmplManifestFile, bytes.Count(l.content[:offset], []byte("\n"))+1)
+}
+
+func (l *linter) add(level, location, format string, vars ...interface{}) {
+	l.findings = append(l.findings, &lintFinding{
+		Level:    level,
+		Location: location,
+		Message:  fmt.Sprintf(format, vars...),
+	})
+}
+
+// countErrors The number of findings at the error level.
+func countErrors(findings []*lintFinding) int {
+	n := 0
+	for _, f := range findings {
+		if f.Level == lintError {
+			n++
+		}
+	}
+
+	return n
+}
+
+// printFindings Write the findings as text, one per line, or as JSON.
+func printFindings(w io.Writer, findings []*lintFinding, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		return enc.Encode(findings)
+	case "text":
+		for _, f := range findings {
+			if _, e := fmt.Fprintln(w, f.String()); e != nil {
+				return e
+			}
+		}
+		errs := countErrors(findings)
+		_, e := fmt.Fprintf(w, lintMsg.Summary+"\n", errs, len(findings)-errs)
+		return e
+	}
+
+	return fmt.Errorf(stderr.LintFormat, format)
+}
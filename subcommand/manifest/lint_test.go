@@ -0,0 +1,114 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintTemplate(t *testing.T) {
+	want := []string{
+		`README.md:3:6: error: placeholder "missing" is used but not declared in the manifest`,
+		`bad.txt:3: error: not a valid Go template: unclosed action started at bad.txt:2`,
+		`template.json:13: warning: placeholder "unused" is declared but never used`,
+		`template.json:5: warning: copyAsIs pattern "*.exe" does not match any file`,
+		`template.json:9: warning: skip pattern "*.bak" does not match any file`,
+		`template.json:8: warning: skip pattern "skipped.txt" may match other files than before, patterns now work as in a .gitignore file, see https://git-scm.com/docs/gitignore#_pattern_format`,
+		`template.json:17: warning: alphaNumeric validator field "unused" is never used`,
+	}
+
+	findings, err := lintTemplate(fixtureDir + ps + "lint-01")
+	if err != nil {
+		t.Fatalf("lintTemplate() error = %v", err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got:\n%v\nwant:\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		wantErr bool
+		check   func(t *testing.T, out []byte)
+	}{
+		{
+			"text",
+			"text",
+			true,
+			func(t *testing.T, out []byte) {
+				if !bytes.HasSuffix(out, []byte("2 error(s), 5 warning(s)\n")) {
+					t.Errorf("want a summary at the end, got %s", out)
+				}
+			},
+		},
+		{
+			"json",
+			"json",
+			true,
+			func(t *testing.T, out []byte) {
+				var findings []*lintFinding
+				if e := json.Unmarshal(out, &findings); e != nil {
+					t.Fatalf("output is not JSON: %v", e)
+				}
+				if len(findings) != 7 {
+					t.Errorf("got %v findings, want 7", len(findings))
+				}
+			},
+		},
+		{"unknown-format", "xml", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			err := lint(fixtureDir+ps+"lint-01"+ps+"template.json", tt.format, out)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("lint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.check != nil {
+				tt.check(t, out.Bytes())
+			}
+		})
+	}
+}
+
+func TestLintNoErrors(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	if err := lint(fixtureDir+ps+"template-06"+ps+"template.json", "text", out); err != nil {
+		t.Errorf("lint() error = %v, output: %s", err, out)
+	}
+}
+
+func TestLintBinary(t *testing.T) {
+	want := []string{
+		`font.woff2: warning: looks binary, it is copied as-is; list it in copyAsIs, or in render to press it`,
+		`template.json:5: warning: render pattern "*.cfg" does not match any file`,
+	}
+
+	findings, err := lintTemplate(fixtureDir + ps + "lint-02")
+	if err != nil {
+		t.Fatalf("lintTemplate() error = %v", err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got:\n%v\nwant:\n%v", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -12,8 +12,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
-	txtParse "text/template/parse"
 )
 
 const (
@@ -24,9 +22,14 @@ const (
 )
 
 type Arguments struct {
-	Cmd  string // command to run.
-	Path string // path to generate a manifest for.
-	Skip string // files to exclude when generating a template manifest
+	Check  bool   // fail when a generated manifest would differ from the existing one.
+	Cmd    string // command to run.
+	DryRun bool   // only show the changes an upgrade would make.
+	Format string // output format of lint findings.
+	Out    string // file to save an upgraded manifest to, instead of in place.
+	Path   string // path to generate a manifest for.
+	Prune  bool   // remove placeholders no longer referenced when generating.
+	Skip   string // files to exclude when generating a template manifest
 }
 
 var (
@@ -34,6 +37,8 @@ var (
 	flags           *flag.FlagSet
 	help            bool
 	generateFlagSet *flag.FlagSet
+	lintFlagSet     *flag.FlagSet
+	upgradeFlagSet  *flag.FlagSet
 	validateFlagSet *flag.FlagSet
 )
 
@@ -44,8 +49,19 @@ func Init() *flag.FlagSet {
 
 	generateFlagSet = flag.NewFlagSet("generate", flag.ExitOnError)
 
+	generateFlagSet.BoolVar(&input.Check, "check", false, UsageMessages["Check"])
+	generateFlagSet.BoolVar(&input.Prune, "prune", false, UsageMessages["Prune"])
 	generateFlagSet.StringVar(&input.Skip, "skip", "", UsageMessages["Skip"])
 
+	lintFlagSet = flag.NewFlagSet("lint", flag.ExitOnError)
+
+	lintFlagSet.StringVar(&input.Format, "format", "text", UsageMessages["Format"])
+
+	upgradeFlagSet = flag.NewFlagSet("upgrade", flag.ExitOnError)
+
+	upgradeFlagSet.BoolVar(&input.DryRun, "dry-run", false, UsageMessages["DryRun"])
+	upgradeFlagSet.StringVar(&input.Out, "out", "", UsageMessages["Out"])
+
 	validateFlagSet = flag.NewFlagSet("validate", flag.ExitOnError)
 
 	return flags
@@ -85,6 +101,14 @@ func parseCmd(ca []string) error {
 		if e := generateFlagSet.Parse(subArgs); e != nil {
 			return fmt.Errorf(msg.Stderr.ParseGenerateInput, e.Error())
 		}
+	case "lint":
+		if e := lintFlagSet.Parse(subArgs); e != nil {
+			return fmt.Errorf(stderr.ParseLintInput, e.Error())
+		}
+	case "upgrade":
+		if e := upgradeFlagSet.Parse(subArgs); e != nil {
+			return fmt.Errorf(stderr.ParseUpgradeInput, e.Error())
+		}
 	case "validate":
 		if e := validateFlagSet.Parse(subArgs); e != nil {
 			return fmt.Errorf(msg.Stderr.ParseValidateInput, e.Error())
@@ -98,7 +122,7 @@ func parseInputPath(path string) (string, error) {
 	// clean up the path.
 	p, e1 := filepath.Abs(path)
 	if e1 != nil {
-		return "", fmt.Errorf(msg.Stderr.NoPath, e1.Error())
+		return "", fmt.Errorf(msg.Stderr.NoPath, path, e1.Error())
 	}
 
 	log.Dbugf(msg.Stdout.TemplatePath, p)
@@ -117,24 +141,55 @@ func Run(ca []string) error {
 		flags.Usage()
 		return fmt.Errorf(msg.Stderr.InvalidCmd, input.Cmd)
 	case "generate":
-		aPath, e := parseInputPath(generateFlagSet.Args()[0])
+		aPath, e := parseInputPath(generateFlagSet.Arg(0))
 		if e != nil || help {
 			return e
 		}
 
-		filename, e1 := generateATemplateManifest(aPath, input.Skip)
+		filename, e1 := GenerateATemplateManifest(aPath, input.Skip, input.Prune, input.Check)
 		if e1 != nil {
 			return e1
 		}
 
-		log.Logf(msg.Stdout.GeneratedManifest, filename)
-	case "validate":
-		aPath, e1 := parseInputPath(validateFlagSet.Args()[0])
-		if e1 != nil || help {
+		if input.Check {
+			log.Logf(stdout.ManifestUpToDate, filename)
+		} else {
+			log.Logf(msg.Stdout.GeneratedManifest, filename)
+		}
+	case "lint":
+		var aPath string
+		if lintFlagSet.NArg() > 0 {
+			aPath = lintFlagSet.Arg(0)
+		}
+
+		filename, e1 := getCleanPath(aPath)
+		if e1 != nil {
+			return e1
+		}
+
+		return lint(filename, input.Format, os.Stdout)
+	case "upgrade":
+		var aPath string
+		if upgradeFlagSet.NArg() > 0 {
+			aPath = upgradeFlagSet.Arg(0)
+		}
+
+		filename, e1 := getCleanPath(aPath)
+		if e1 != nil {
 			return e1
 		}
 
-		ip := getCleanPath(aPath)
+		return upgradeManifest(filename, input.Out, input.DryRun)
+	case "validate":
+		var aPath string
+		if validateFlagSet.NArg() > 0 {
+			aPath = validateFlagSet.Arg(0)
+		}
+
+		ip, e1 := getCleanPath(aPath)
+		if e1 != nil {
+			return e1
+		}
 
 		e2 := press.ValidateManifest(ip)
 		if e2 != nil {
@@ -149,8 +204,11 @@ func Run(ca []string) error {
 	return nil
 }
 
-// generateATemplateManifest Make a JSON file with your templates placeholders.
-func generateATemplateManifest(tmplPath, skip string) (string, error) {
+// GenerateATemplateManifest Make a JSON file with your templates placeholders.
+// Descriptions of placeholders already in the manifest are kept, and those no
+// longer referenced are only removed when pruning. When checking, nothing is
+// saved and an error is returned if the manifest is out of date.
+func GenerateATemplateManifest(tmplPath, skip string, prune, check bool) (string, error) {
 	log.Logf("generating manifest")
 	if !fsio.Exist(tmplPath) {
 		return "", fmt.Errorf(msg.Stderr.PathNotExist, tmplPath)
@@ -164,18 +222,13 @@ func generateATemplateManifest(tmplPath, skip string) (string, error) {
 	}
 
 	// check for existing template manifest and load it
-	existing, e2 := press.ReadTemplateJson(filename)
+	existing, e2 := readUpgraded(filename)
 	if e2 != nil {
 		log.Infof(e2.Error())
 	}
 
-	if existing != nil { // merge old into the new updating it at the same time.
-		tm.CopyAsIs = existing.CopyAsIs
-		tm.EmptyDirFile = existing.EmptyDirFile
-		tm.Placeholders = existing.Placeholders
-		tm.Skip = existing.Skip
-		tm.Substitute = existing.Substitute
-		tm.Validation = existing.Validation
+	if existing != nil { // keep all of the old, migrated to the current version.
+		tm = existing
 	}
 
 	if skip != "" {
@@ -185,166 +238,158 @@ func generateATemplateManifest(tmplPath, skip string) (string, error) {
 
 	log.Infof("skip = %v\n", tm.Skip)
 
-	// Traverse the path recursively, filtering out files that should be excluded
-	templates, e3 := parseDir(tmplPath, tm)
-	if e3 != nil {
-		return "", e3
+	partials, e4 := press.LoadPartials(tmplPath, tm)
+	if e4 != nil {
+		return "", e4
 	}
 
-	actions := make(map[string]string)
+	refs := walkPartials(partials)
+
+	// Files of the substitute directory are pressed too, over the root of
+	// the template.
+	w := &press.Walker{Substitute: true, Tm: tm}
 
 	// Parse the file as a template and extract all actions from each file.
-	for _, tmpl := range templates {
-		relativePath := strings.TrimLeft(strings.ReplaceAll(tmpl, tmplPath, ""), "\\/")
-		//fmt.Printf("relativePath = %v\n", relativePath)
-
-		// Don't do anything with the files in this list.
-		if press.InSkipArray(relativePath, tm.Skip) {
-			log.Infof(msg.Stdout.Skipping, tmpl)
-			fmt.Printf("skipping %v\n", tmpl)
-			continue
+	e3 := w.Walk(tmplPath, func(tmpl, relativePath string, d fs.DirEntry) error {
+		if d.Name() == tm.EmptyDirFile || tm.PreservesLink(d) || press.InSkipArray(relativePath, tm.CopyAsIs) {
+			return nil
 		}
 
 		fmt.Printf("checking %v\n", tmpl)
 
-		bName := filepath.Base(tmpl)
-		t, e := template.New(bName).Funcs(press.FuncMap).ParseFiles(tmpl)
-		if e != nil {
-			return "", fmt.Errorf(msg.Stderr.ParsingFile, tmpl, e.Error())
+		content, e5 := os.ReadFile(tmpl)
+		if e5 != nil {
+			return fmt.Errorf(msg.Stderr.CannotReadFile, tmpl, e5.Error())
 		}
 
-		listTemplateFields(t, actions)
-	}
+		// Binary files are copied as-is, so they have no placeholders.
+		if tm.ChecksBinary(relativePath) && press.IsBinary(content) {
+			return nil
+		}
 
-	tm.Placeholders = actions
+		text, e6 := tm.DecodeText(relativePath, content)
+		if e6 != nil {
+			return e6
+		}
 
-	if e := saveFile(filename, tm); e != nil {
-		return "", e
-	}
+		fileRefs, e7 := walkFile(relativePath, relativePath, text, tm, partials)
+		if e7 != nil {
+			return fmt.Errorf(msg.Stderr.ParsingFile, tmpl, e7.Error())
+		}
 
-	return filename, nil
-}
+		refs = append(refs, fileRefs...)
 
-func getCleanPath(aPath string) string {
-	var wf string
+		return nil
+	})
+	if e3 != nil {
+		return "", e3
+	}
 
-	if aPath == "" { // transform to current directory
-		clean, e := os.Getwd()
-		if e != nil {
-			m := fmt.Sprintf("could not access the current working dirctory %q: %v", wf, e.Error())
-			panic(m)
-		}
-		wf = clean
+	found := make(map[string]string)
+	for _, ref := range refs {
+		found[ref.name] = ""
 	}
 
-	if fsio.Exist(aPath) {
-		clean, e := filepath.Abs(aPath)
-		if e != nil {
-			m := fmt.Sprintf("there is an issue with the path %q: %v", aPath, e.Error())
-			panic(m)
+	placeholders, changes := reconcilePlaceholders(tm.Placeholders, found, prune)
+	changes.print()
+
+	if check {
+		if changes.outOfDate() {
+			return "", fmt.Errorf(stderr.ManifestOutOfDate, filename)
 		}
-		wf = clean
+		return filename, nil
 	}
 
-	if !strings.Contains(wf, ".json") {
-		wf = wf + ps + press.TmplManifestFile
-	}
-	if !fsio.Exist(wf) {
-		panic(fmt.Sprintf("inavlid path %v", aPath))
-	}
+	tm.Placeholders = placeholders
+	tm.Types = reconcileTypes(tm.Types, inferTypes(refs), placeholders)
+	press.SuggestValidation(tm)
 
-	return wf
-}
+	if e := saveFile(filename, tm); e != nil {
+		return "", e
+	}
 
-// listTemplateFields list actions in Go templates. See SO answer: https://stackoverflow.com/a/40584967/419097
-func listTemplateFields(t *template.Template, res map[string]string) {
-	listNodeFields(t.Tree.Root, res)
+	return filename, nil
 }
 
-// parseDir Recursively walk a directory parsing all files along the way as Go templates.
-func parseDir(path string, tm *press.TmplManifest) ([]string, error) {
-	// Normalize the path separator in these 2 variables before comparing them.
-	nPath := fsio.Normalize(path)
+// readUpgraded Read a manifest migrated to the current version, the same as
+// an upgrade, so it is saved in the current shape.
+func readUpgraded(filename string) (*press.TmplManifest, error) {
+	content, e1 := os.ReadFile(filename)
+	if e1 != nil {
+		return nil, fmt.Errorf(msg.Stderr.CannotReadFile, filename, e1.Error())
+	}
 
-	var files []string
+	upgraded, _, e2 := press.UpgradeManifest(content)
+	if e2 != nil {
+		return nil, fmt.Errorf(stderr.UpgradingManifest, filename, e2.Error())
+	}
 
-	// Recursively walk the template directory.
-	err := filepath.Walk(nPath, func(fPath string, info fs.FileInfo, err error) error {
+	return press.NewTmplManifest(upgraded)
+}
 
-		file, e1 := filterFile(fPath, nPath, info, err, tm)
-		if err != nil {
-			return e1
-		}
+// upgradeManifest Migrate a manifest to the current version, print the
+// changes, then save it in place or to the out file.
+func upgradeManifest(filename, out string, dryRun bool) error {
+	content, e1 := os.ReadFile(filename)
+	if e1 != nil {
+		return fmt.Errorf(msg.Stderr.CannotReadFile, filename, e1.Error())
+	}
 
-		if file != "" {
-			files = append(files, file)
-		}
+	upgraded, steps, e2 := press.UpgradeManifest(content)
+	if e2 != nil {
+		return fmt.Errorf(stderr.UpgradingManifest, filename, e2.Error())
+	}
 
+	if len(steps) == 0 {
+		log.Logf(stdout.ManifestIsCurrent, filename, press.SchemaVersion)
 		return nil
-	})
-
-	if err != nil {
-		return nil, err
 	}
 
-	return files, nil
-}
-
-// filterFile
-func filterFile(sourcePath, nPath string, info os.FileInfo, wErr error, tm *press.TmplManifest) (string, error) {
-	if wErr != nil {
-		return "", wErr
+	if out == "" {
+		out = filename
 	}
 
-	if info.IsDir() {
-		return "", nil
-	}
+	log.Logf(stdout.UpgradeSteps, filename, strings.Join(steps, " -> "))
+	fmt.Print(unifiedDiff(filename, out, content, upgraded, 3))
 
-	// skip certain .git files/directories
-	if strings.Contains(sourcePath, ps+GitConfigDir+ps) {
-		return "", nil
+	if dryRun {
+		return nil
 	}
 
-	currFile := filepath.Base(sourcePath)
-
-	// TODO: Add globbing is added. filepath.Glob(pattern)
-	if currFile == tm.EmptyDirFile || currFile == press.TmplManifestFile { // Use an exclusion list, include every file by default.
-		return "", nil
+	if e := os.WriteFile(out, upgraded, 0644); e != nil {
+		return fmt.Errorf(stderr.SavingManifest, out, e.Error())
 	}
 
-	// Normalize the path separator in these 2 variables before comparing them.
-	normSourcePath := strings.ReplaceAll(sourcePath, "/", ps)
-	normSourcePath = strings.ReplaceAll(normSourcePath, "\\", ps)
+	log.Logf(stdout.UpgradedManifest, out)
 
-	// Skip files that are listed in the excludes.
-	if tm.CopyAsIs != nil {
-		fileToCheck := strings.ReplaceAll(normSourcePath, nPath, "")
-		fileToCheck = strings.ReplaceAll(fileToCheck, ps, "")
+	return nil
+}
 
-		for _, exclude := range tm.CopyAsIs {
-			fileToCheckB := strings.ReplaceAll(exclude, "\\", "")
-			fileToCheckB = strings.ReplaceAll(exclude, "/", "")
+func getCleanPath(aPath string) (string, error) {
+	wf := aPath
 
-			if fileToCheckB == fileToCheck {
-				return "", nil
-			}
+	if wf == "" { // transform to current directory
+		cwd, e := os.Getwd()
+		if e != nil {
+			return "", fmt.Errorf(stderr.ListWorkingDirectory, e.Error())
 		}
+		wf = cwd
 	}
 
-	return sourcePath, nil
-}
+	clean, e1 := filepath.Abs(wf)
+	if e1 != nil {
+		return "", fmt.Errorf(msg.Stderr.NoPath, wf, e1.Error())
+	}
 
-// listTemplateFields list actions in Go templates. See SO answer: https://stackoverflow.com/a/40584967/419097
-func listNodeFields(node txtParse.Node, res map[string]string) {
-	if node.Type() == txtParse.NodeAction {
-		res[strings.Trim(node.String(), "{}.")] = ""
+	if !strings.HasSuffix(clean, ".json") {
+		clean = clean + ps + press.TmplManifestFile
 	}
 
-	if ln, ok := node.(*txtParse.ListNode); ok {
-		for _, n := range ln.Nodes {
-			listNodeFields(n, res)
-		}
+	if !fsio.Exist(clean) {
+		return "", fmt.Errorf(msg.Stderr.PathNotExist, clean)
 	}
+
+	return clean, nil
 }
 
 // save configuration file.
@@ -356,7 +401,7 @@ func saveFile(jsonFile string, tm *press.TmplManifest) error {
 	}
 
 	// Write the template.json manifest to disk.
-	if e := os.WriteFile(jsonFile, data, 0744); e != nil {
+	if e := os.WriteFile(jsonFile, data, 0644); e != nil {
 		return e
 	}
 
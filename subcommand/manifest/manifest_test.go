@@ -4,8 +4,10 @@ import (
 	"github.com/kohirens/stdlib/fsio"
 	"github.com/kohirens/stdlib/git"
 	"github.com/kohirens/tmplpress/internal/press"
+	"github.com/kohirens/tmplpress/internal/test"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -14,6 +16,12 @@ const (
 	tmpDir     = "tmp"
 )
 
+func TestMain(m *testing.M) {
+	press.UseSchemas(test.Schemas())
+
+	os.Exit(m.Run())
+}
+
 func TestGenerateATemplateJson(runner *testing.T) {
 	testCases := []struct {
 		name string
@@ -39,7 +47,7 @@ func TestGenerateATemplateJson(runner *testing.T) {
 	for _, tc := range testCases {
 		runner.Run(tc.name, func(t *testing.T) {
 			repoPath := git.CloneFromBundle(tc.repo, tmpDir, fixtureDir, ps)
-			got, err := generateATemplateManifest(repoPath, "")
+			got, err := GenerateATemplateManifest(repoPath, "", false, false)
 			if err != nil {
 				t.Errorf("want nil, got: %q", err.Error())
 			}
@@ -89,6 +97,305 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestRunGenerateReconcile(t *testing.T) {
+	dir := tmpDir + ps + "reconcile"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir, 0774)
+	_ = os.WriteFile(dir+ps+"README.md", []byte("{{ .appTitle }} {{ .name }}"), 0774)
+	_ = os.WriteFile(dir+ps+press.TmplManifestFile, []byte(`{
+    "version": "2.3.0",
+    "placeholders": {"appTitle": "The title of the app", "old": "No longer used"}
+}`), 0774)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		want    map[string]string
+	}{
+		{
+			"check-out-of-date",
+			[]string{"-check"},
+			true,
+			map[string]string{"appTitle": "The title of the app", "old": "No longer used"},
+		},
+		{
+			"keep-descriptions",
+			nil,
+			false,
+			map[string]string{"appTitle": "The title of the app", "name": "", "old": "No longer used"},
+		},
+		{
+			"prune",
+			[]string{"-prune"},
+			false,
+			map[string]string{"appTitle": "The title of the app", "name": ""},
+		},
+		{
+			"check-up-to-date",
+			[]string{"-check"},
+			false,
+			map[string]string{"appTitle": "The title of the app", "name": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		Init()
+
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"generate"}, tt.args...)
+			err := Run(append(args, dir))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			b, _ := os.ReadFile(dir + ps + press.TmplManifestFile)
+			tm, _ := press.NewTmplManifest(b)
+
+			if !reflect.DeepEqual(tm.Placeholders, tt.want) {
+				t.Errorf("got %v, want %v", tm.Placeholders, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateInfersTypes(t *testing.T) {
+	dir := tmpDir + ps + "infer-types"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir, 0774)
+	_ = os.WriteFile(dir+ps+"Dockerfile", []byte(`{{ if .useDocker }}EXPOSE {{ .port | printf "%d" }}{{ end }}`), 0774)
+
+	filename, err := GenerateATemplateManifest(dir, "", false, false)
+	if err != nil {
+		t.Fatalf("GenerateATemplateManifest() error = %v", err)
+	}
+
+	b, _ := os.ReadFile(filename)
+	tm, _ := press.NewTmplManifest(b)
+
+	want := map[string]string{"port": press.TypeInt, "useDocker": press.TypeBool}
+	if !reflect.DeepEqual(tm.Types, want) {
+		t.Errorf("got types %v, want %v", tm.Types, want)
+	}
+
+	if len(tm.SuggestedValidation) != 2 {
+		t.Errorf("got %v suggested validators, want 2", len(tm.SuggestedValidation))
+	}
+
+	if errs, _ := press.ValidateManifestSchema(b); len(errs) > 0 {
+		t.Errorf("generated manifest does not conform to the schema: %v", errs)
+	}
+}
+
+func TestGenerateUpgrades(t *testing.T) {
+	dir := tmpDir + ps + "generate-upgrades"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir, 0774)
+	_ = os.WriteFile(dir+ps+"README.md", []byte("# {{ .appName }} by {{ .owner }}\n"), 0774)
+	_ = os.WriteFile(dir+ps+press.TmplManifestFile, []byte(`{
+    "version": "1.2",
+    "placeholders": {"appName": "Name of the app"},
+    "excludes": ["*.png"]
+}`), 0774)
+
+	filename, err := GenerateATemplateManifest(dir, "", false, false)
+	if err != nil {
+		t.Fatalf("GenerateATemplateManifest() error = %v", err)
+	}
+
+	if e := press.ValidateManifest(filename); e != nil {
+		t.Errorf("generated manifest is not valid: %v", e)
+	}
+
+	b, _ := os.ReadFile(filename)
+	tm, _ := press.NewTmplManifest(b)
+
+	if tm.Version != press.SchemaVersion {
+		t.Errorf("got version %v, want %v", tm.Version, press.SchemaVersion)
+	}
+
+	if !reflect.DeepEqual(tm.CopyAsIs, []string{"*.png"}) {
+		t.Errorf("got copyAsIs %v, want the migrated excludes", tm.CopyAsIs)
+	}
+
+	want := map[string]string{"appName": "Name of the app", "owner": ""}
+	if !reflect.DeepEqual(tm.Placeholders, want) {
+		t.Errorf("got %v, want %v", tm.Placeholders, want)
+	}
+}
+
+func TestGenerateDelimiters(t *testing.T) {
+	dir := tmpDir + ps + "delimiters"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir+ps+"chart", 0774)
+	_ = os.WriteFile(dir+ps+"chart"+ps+"deployment.yaml", []byte("name: [[ .appName ]]\nimage: {{ .Values.image }}"), 0774)
+	_ = os.WriteFile(dir+ps+press.TmplManifestFile, []byte(`{
+    "version": "2.5.0",
+    "delimiters": [{"files": ["chart/*"], "left": "[[", "right": "]]"}]
+}`), 0774)
+
+	filename, err := GenerateATemplateManifest(dir, "", false, false)
+	if err != nil {
+		t.Fatalf("GenerateATemplateManifest() error = %v", err)
+	}
+
+	b, _ := os.ReadFile(filename)
+	tm, _ := press.NewTmplManifest(b)
+
+	want := map[string]string{"appName": ""}
+	if !reflect.DeepEqual(tm.Placeholders, want) {
+		t.Errorf("got %v, want %v", tm.Placeholders, want)
+	}
+
+	if len(tm.Delimiters) != 1 {
+		t.Errorf("the delimiters were not kept, got %v", tm.Delimiters)
+	}
+
+	findings, _ := lintTemplate(dir)
+	if n := countErrors(findings); n > 0 {
+		t.Errorf("lint found %v errors: %v", n, findings)
+	}
+}
+
+func TestGeneratePartials(t *testing.T) {
+	dir := tmpDir + ps + "partials"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir+ps+"_partials", 0774)
+	_ = os.WriteFile(dir+ps+"_partials"+ps+"license.tmpl", []byte(`{{ define "licenseHeader" }}// Copyright {{ .owner }}{{ end }}`), 0774)
+	_ = os.WriteFile(dir+ps+"main.go", []byte(`{{ template "licenseHeader" . }}`+"\npackage {{ .appName }}"), 0774)
+	_ = os.WriteFile(dir+ps+press.TmplManifestFile, []byte(`{"version": "2.6.0", "partials": "_partials"}`), 0774)
+
+	filename, err := GenerateATemplateManifest(dir, "", false, false)
+	if err != nil {
+		t.Fatalf("GenerateATemplateManifest() error = %v", err)
+	}
+
+	b, _ := os.ReadFile(filename)
+	tm, _ := press.NewTmplManifest(b)
+
+	want := map[string]string{"appName": "", "owner": ""}
+	if !reflect.DeepEqual(tm.Placeholders, want) {
+		t.Errorf("got %v, want %v", tm.Placeholders, want)
+	}
+
+	findings, _ := lintTemplate(dir)
+	if len(findings) > 0 {
+		t.Errorf("want no findings, got %v", findings)
+	}
+}
+
+func TestGenerateFrontMatter(t *testing.T) {
+	dir := tmpDir + ps + "front-matter"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir, 0774)
+	_ = os.WriteFile(dir+ps+"main.go", []byte("---tmplpress\n{\"path\": \"cmd/{{.appName}}/main.go\", \"if\": \"{{.withCli}}\"}\n---\npackage main // {{ .owner }}\n"), 0774)
+	_ = os.WriteFile(dir+ps+"notes.md", []byte("---tmplpress\n{\"raw\": true}\n---\n{{ .notAPlaceholder }}\n"), 0774)
+	_ = os.WriteFile(dir+ps+"values.yaml", []byte("---tmplpress\n{\"delimiters\": {\"left\": \"[[\", \"right\": \"]]\"}}\n---\nport: [[ .port ]]\nimage: {{ .Values.image }}\n"), 0774)
+	_ = os.WriteFile(dir+ps+press.TmplManifestFile, []byte(`{"version": "2.6.0"}`), 0774)
+
+	filename, err := GenerateATemplateManifest(dir, "", false, false)
+	if err != nil {
+		t.Fatalf("GenerateATemplateManifest() error = %v", err)
+	}
+
+	b, _ := os.ReadFile(filename)
+	tm, _ := press.NewTmplManifest(b)
+
+	want := map[string]string{"appName": "", "owner": "", "port": "", "withCli": ""}
+	if !reflect.DeepEqual(tm.Placeholders, want) {
+		t.Errorf("got %v, want %v", tm.Placeholders, want)
+	}
+
+	// Lines of the front matter are counted in the location of a finding.
+	_ = os.WriteFile(dir+ps+"README.md", []byte("---tmplpress\n{}\n---\n# {{ .title }}\n"), 0774)
+	_ = os.WriteFile(dir+ps+"bad.txt", []byte("---tmplpress\n{\"output\": \"a.txt\"}\n---\n"), 0774)
+
+	findings, _ := lintTemplate(dir)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+
+	wantFindings := []string{
+		`README.md:4:5: error: placeholder "title" is used but not declared in the manifest`,
+		`bad.txt: error: invalid front matter: could not decode the front matter: json: unknown field "output"`,
+	}
+	if !reflect.DeepEqual(got, wantFindings) {
+		t.Errorf("got:\n%v\nwant:\n%v", strings.Join(got, "\n"), strings.Join(wantFindings, "\n"))
+	}
+}
+
+func TestGenerateForEach(t *testing.T) {
+	dir := tmpDir + ps + "for-each"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir, 0774)
+	_ = os.WriteFile(dir+ps+"service.yaml", []byte("app: {{ .appName }}\nname: {{ .service.name }}\n"), 0774)
+	_ = os.WriteFile(dir+ps+press.TmplManifestFile, []byte(`{
+    "version": "2.7.0",
+    "forEach": [
+        {"files": ["service.yaml"], "list": "services", "as": "service", "path": "{{ .group }}/{{ .service.name }}.yaml"},
+        {"files": ["*.txt"], "list": "services", "path": "{{ .item }}.txt"}
+    ]
+}`), 0774)
+
+	filename, err := GenerateATemplateManifest(dir, "", false, false)
+	if err != nil {
+		t.Fatalf("GenerateATemplateManifest() error = %v", err)
+	}
+
+	b, _ := os.ReadFile(filename)
+	tm, _ := press.NewTmplManifest(b)
+
+	want := map[string]string{"appName": "", "group": "", "services": ""}
+	if !reflect.DeepEqual(tm.Placeholders, want) {
+		t.Errorf("got %v, want %v", tm.Placeholders, want)
+	}
+
+	if tm.Types["services"] != press.TypeList {
+		t.Errorf("got type %q, want %q", tm.Types["services"], press.TypeList)
+	}
+
+	findings, _ := lintTemplate(dir)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+
+	wantFindings := []string{`template.json:13: warning: forEach pattern "*.txt" does not match any file`}
+	if !reflect.DeepEqual(got, wantFindings) {
+		t.Errorf("got:\n%v\nwant:\n%v", strings.Join(got, "\n"), strings.Join(wantFindings, "\n"))
+	}
+}
+
+func TestGenerateData(t *testing.T) {
+	dir := tmpDir + ps + "data"
+	_ = os.RemoveAll(dir)
+	_ = os.MkdirAll(dir, 0774)
+	_ = os.WriteFile(dir+ps+"deploy.yaml", []byte("# {{ .Press.Year }}\nowner: {{ index .Data.owners .appName }}\n"), 0774)
+	_ = os.WriteFile(dir+ps+"owners.json", []byte(`{"billing": "{{ not a template"}`), 0774)
+	_ = os.WriteFile(dir+ps+press.TmplManifestFile, []byte(`{"version": "2.8.0", "data": ["owners.json"]}`), 0774)
+
+	filename, err := GenerateATemplateManifest(dir, "", false, false)
+	if err != nil {
+		t.Fatalf("GenerateATemplateManifest() error = %v", err)
+	}
+
+	b, _ := os.ReadFile(filename)
+	tm, _ := press.NewTmplManifest(b)
+
+	want := map[string]string{"appName": ""}
+	if !reflect.DeepEqual(tm.Placeholders, want) {
+		t.Errorf("got %v, want %v", tm.Placeholders, want)
+	}
+
+	findings, _ := lintTemplate(dir)
+	if len(findings) > 0 {
+		t.Errorf("want no findings, got %v", findings)
+	}
+}
+
 func TestRunValidate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -101,6 +408,11 @@ func TestRunValidate(t *testing.T) {
 		{"placeholder-not-found", fixtureDir + ps + "template-2.2.0-02.json", "validate", true},
 		{"empty-regexp", fixtureDir + ps + "template-2.2.0-03.json", "validate", true},
 		{"invalid-regexp", fixtureDir + ps + "template-2.2.0-04.json", "validate", true},
+		{"unknown-property", fixtureDir + ps + "template-2.2.0-05.json", "validate", true},
+		{"wrong-type", fixtureDir + ps + "template-2.2.0-06.json", "validate", true},
+		{"unsupported-version", fixtureDir + ps + "template-9.0.0-01.json", "validate", true},
+		{"lint-errors", fixtureDir + ps + "lint-01", "lint", true},
+		{"lint-no-errors", fixtureDir + ps + "template-06", "lint", false},
 	}
 
 	for _, tt := range tests {
@@ -115,6 +427,77 @@ func TestRunValidate(t *testing.T) {
 	}
 }
 
+func TestRunUpgrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     *press.TmplManifest
+		wantErr  bool
+	}{
+		{
+			"from-1.2",
+			fixtureDir + ps + "template-1.2.json",
+			&press.TmplManifest{
+				CopyAsIs: []string{".chglog/CHANGELOG.tpl.md", ".devcontainer/download-vs-code-server.sh"},
+				Placeholders: map[string]string{
+					"appName":  "Application name, the formal name with capitalization and spaces",
+					"codeName": "Programmatic name to be used",
+					"repoOrg":  "Repository organization, for example `github.com/kohirens`",
+				},
+				Substitute: "replace",
+				Version:    press.SchemaVersion,
+			},
+			false,
+		},
+		{"too-new", fixtureDir + ps + "template-9.0.0-01.json", nil, true},
+	}
+
+	_ = os.MkdirAll(tmpDir, 0774)
+
+	for _, tt := range tests {
+		Init()
+
+		t.Run(tt.name, func(t *testing.T) {
+			out := tmpDir + ps + "upgrade-" + tt.name + ".json"
+			before, _ := os.ReadFile(tt.template)
+
+			err := Run([]string{"upgrade", "-out", out, tt.template})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			after, _ := os.ReadFile(tt.template)
+			if string(before) != string(after) {
+				t.Errorf("upgrade with -out changed the original %v", tt.template)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			b, _ := os.ReadFile(out)
+			got, e := press.NewTmplManifest(b)
+			if e != nil {
+				t.Fatalf("could not read the upgraded manifest: %v", e)
+			}
+
+			got.Validation = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+
+			errs, _ := press.ValidateManifestSchema(b)
+			if len(errs) > 0 {
+				t.Errorf("upgraded manifest does not conform to the schema: %v", errs)
+			}
+
+			if info, e := os.Stat(out); e == nil && info.Mode().Perm()&0111 != 0 {
+				t.Errorf("upgraded manifest should not be executable, got %v", info.Mode())
+			}
+		})
+	}
+}
+
 func Test_getCleanPathEmpty(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -132,8 +515,8 @@ func Test_getCleanPathEmpty(t *testing.T) {
 			defer os.Chdir(wd)
 
 			want := repoPath + ps + press.TmplManifestFile
-			if got := getCleanPath(tt.aPath); got != want {
-				t.Errorf("getCleanPath() = %v, want %v", got, want)
+			if got, e := getCleanPath(tt.aPath); e != nil || got != want {
+				t.Errorf("getCleanPath() = %v, %v, want %v", got, e, want)
 			}
 		})
 	}
@@ -151,8 +534,23 @@ func Test_getCleanPath(t *testing.T) {
 		want := repoPath + ps + press.TmplManifestFile
 
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getCleanPath(repoPath); got != want {
-				t.Errorf("getCleanPath() = %v, want %v", got, want)
+			if got, e := getCleanPath(repoPath); e != nil || got != want {
+				t.Errorf("getCleanPath() = %v, %v, want %v", got, e, want)
+			}
+		})
+	}
+}
+
+func TestRunMissingPath(t *testing.T) {
+	missing := tmpDir + ps + "no-such-template"
+
+	for _, cmd := range []string{"lint", "upgrade", "validate"} {
+		t.Run(cmd, func(t *testing.T) {
+			Init()
+
+			err := Run([]string{cmd, missing})
+			if err == nil || !strings.Contains(err.Error(), "could not locate the path") {
+				t.Errorf("Run(%v) error = %v, want the path cannot be found", cmd, err)
 			}
 		})
 	}
@@ -2,18 +2,80 @@ package manifest
 
 var stderr = struct {
 	EncodingJson         string
+	LintFailed           string
+	LintFormat           string
 	ListWorkingDirectory string
+	ManifestOutOfDate    string
+	ParseLintInput       string
+	ParseUpgradeInput    string
 	SavingManifest       string
+	UpgradingManifest    string
 }{
 	EncodingJson:         "could not marshall actions in file %v, error: %v",
+	LintFailed:           "lint found %d error(s) in template %v",
+	LintFormat:           "unknown output format %q, use text or json",
 	ListWorkingDirectory: "could not get current working directory, %v",
+	ManifestOutOfDate:    "%v is out of date with the placeholders in the template, run generate to update it",
+	ParseLintInput:       "could not parse lint input: %v",
+	ParseUpgradeInput:    "could not parse upgrade input: %v",
 	SavingManifest:       "could not save file %v, error: %v",
+	UpgradingManifest:    "could not upgrade %v, %v",
+}
+
+var stdout = struct {
+	InferredType        string
+	ManifestIsCurrent   string
+	ManifestUpToDate    string
+	PlaceholdersAdded   string
+	PlaceholdersKept    string
+	PlaceholdersRemoved string
+	PlaceholdersUnused  string
+	UpgradedManifest    string
+	UpgradeSteps        string
+}{
+	InferredType:        "inferred placeholder %v is a %v",
+	ManifestIsCurrent:   "%v is already at version %v, nothing to upgrade",
+	ManifestUpToDate:    "%v is up to date",
+	PlaceholdersAdded:   "added %d placeholder(s): %v",
+	PlaceholdersKept:    "kept %d placeholder(s): %v",
+	PlaceholdersRemoved: "removed %d placeholder(s): %v",
+	PlaceholdersUnused:  "%d placeholder(s) no longer referenced in the template, use -prune to remove them: %v",
+	UpgradedManifest:    "upgraded manifest saved to %v",
+	UpgradeSteps:        "upgrading %v through versions %v",
+}
+
+// lintMsg Messages of the findings reported by lint.
+var lintMsg = struct {
+	Binary            string
+	FrontMatter       string
+	ParseFailed       string
+	PatternChanged    string
+	Summary           string
+	Undeclared        string
+	UnmatchedGlob     string
+	UnusedPlaceholder string
+	UnusedValidator   string
+}{
+	Binary:            "looks binary, it is copied as-is; list it in copyAsIs, or in render to press it",
+	FrontMatter:       "invalid front matter: %v",
+	ParseFailed:       "not a valid Go template: %v",
+	PatternChanged:    "%v pattern %q may match other files than before, patterns now work as in a .gitignore file, see https://git-scm.com/docs/gitignore#_pattern_format",
+	Summary:           "%d error(s), %d warning(s)",
+	Undeclared:        "placeholder %q is used but not declared in the manifest",
+	UnmatchedGlob:     "%v pattern %q does not match any file",
+	UnusedPlaceholder: "placeholder %q is declared but never used",
+	UnusedValidator:   "%v validator field %q is never used",
 }
 
 var UsageMessages = map[string]string{
 	"manifest": "Perform operations on the template manifest file.",
 	"help":     "Display this usage information.",
 	"Skip":     "skip files when generating the manifest.",
+	"Check":    "fail, without saving, when the manifest is out of date with the template.",
+	"Prune":    "remove placeholders no longer referenced in the template.",
+	"DryRun":   "show the changes an upgrade would make without saving them.",
+	"Out":      "save the upgraded manifest to this file instead of in place.",
+	"Format":   "output format of the findings, text or json.",
 }
 
 // UsageTmpl Usage information template of this command.
@@ -23,13 +85,35 @@ Usage: {{.AppName}} {{.Command}} <command> [path/to/template.json]
 The current directory will be searched for a "template.json" if no path is
 given.
 
-generate
+generate [-check] [-prune] [-skip <files>]
 	Generate a template manifest in the {{.AppName}} schema format containing any
 	placeholders found in the directory. This is a quality-of-life tool to help
 	build new or update an existing template manifest file as changes to the
 	template are made. Reducing human error of syncing placeholders as they are
 	added, removed, or updated.
 
+	Descriptions of existing placeholders are kept. Placeholders no longer
+	referenced are reported, and only removed with -prune. Use -check in CI to
+	fail when the manifest is out of date, without saving it.
+
+	The types of placeholders are inferred from how they are used, such as a
+	bool for {{"{{"}}if .UseDocker{{"}}"}}, and saved in "types". Validation for
+	them is suggested in "suggestedValidation", move the ones that are correct
+	to "validation".
+
+lint [-format text|json] [path/to/template]
+	Parse every file of the template that would be pressed, reporting:
+	placeholders used but not declared, declared placeholders never used,
+	copyAsIs, render and skip patterns that match no file, files that are not
+	valid Go templates, files that look binary but are not in copyAsIs, and
+	validator fields never used. Exits with an error when any errors are found,
+	so it can be used in CI.
+
+upgrade [-dry-run] [-out <file>]
+	Upgrade a template manifest written for an older version of the schema to
+	the current version, applying each migration in order. The changes are
+	shown as a diff, then saved in place, or to the file given with -out.
+
 validate
 	Validate a template.json conforms to the template.schema.json specification.
 
@@ -37,6 +121,12 @@ examples:
 
 	$ {{.AppName}} {{.Command}} generate ./template.json
 
+	$ {{.AppName}} {{.Command}} generate -check ./template.json
+
+	$ {{.AppName}} {{.Command}} lint -format json ./path/to/template
+
+	$ {{.AppName}} {{.Command}} upgrade -out ./template-new.json ./template.json
+
 	$ {{.AppName}} {{.Command}} validate ./template.json
 
 `
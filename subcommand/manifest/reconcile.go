@@ -0,0 +1,89 @@
+package manifest
+
+import (
+	"github.com/kohirens/stdlib/log"
+	"sort"
+	"strings"
+)
+
+// placeholderChanges How the placeholders of a manifest differ from the ones
+// referenced in the template.
+type placeholderChanges struct {
+	added   []string
+	kept    []string
+	removed []string // no longer referenced and pruned.
+	unused  []string // no longer referenced, but not pruned.
+}
+
+// outOfDate Indicates the manifest does not match the template.
+func (c *placeholderChanges) outOfDate() bool {
+	return len(c.added) > 0 || len(c.removed) > 0 || len(c.unused) > 0
+}
+
+// print Show a summary of the changes.
+func (c *placeholderChanges) print() {
+	log.Logf(stdout.PlaceholdersAdded, len(c.added), strings.Join(c.added, ", "))
+	log.Logf(stdout.PlaceholdersKept, len(c.kept), strings.Join(c.kept, ", "))
+	log.Logf(stdout.PlaceholdersRemoved, len(c.removed), strings.Join(c.removed, ", "))
+
+	if len(c.unused) > 0 {
+		log.Warnf(stdout.PlaceholdersUnused, len(c.unused), strings.Join(c.unused, ", "))
+	}
+}
+
+// reconcilePlaceholders Merge the placeholders found in a template with those
+// in the manifest. Descriptions are kept for placeholders still in use, new
+// ones get an empty description, and ones no longer referenced are only
+// removed when pruning.
+func reconcilePlaceholders(existing, found map[string]string, prune bool) (map[string]string, *placeholderChanges) {
+	res := make(map[string]string, len(found))
+	changes := &placeholderChanges{}
+
+	for name := range found {
+		if desc, ok := existing[name]; ok {
+			res[name] = desc
+			changes.kept = append(changes.kept, name)
+		} else {
+			res[name] = ""
+			changes.added = append(changes.added, name)
+		}
+	}
+
+	for name, desc := range existing {
+		if _, ok := found[name]; ok {
+			continue
+		}
+
+		if prune {
+			changes.removed = append(changes.removed, name)
+		} else {
+			res[name] = desc
+			changes.unused = append(changes.unused, name)
+		}
+	}
+
+	sort.Strings(changes.added)
+	sort.Strings(changes.kept)
+	sort.Strings(changes.removed)
+	sort.Strings(changes.unused)
+
+	return res, changes
+}
+
+// reconcileTypes Keep the types already in the manifest, since the designer
+// may have corrected them, and add those inferred for placeholders without
+// one. Types of placeholders no longer in the manifest are dropped.
+func reconcileTypes(existing, inferred, placeholders map[string]string) map[string]string {
+	res := make(map[string]string)
+
+	for name := range placeholders {
+		if typ, ok := existing[name]; ok {
+			res[name] = typ
+		} else if typ, found := inferred[name]; found {
+			res[name] = typ
+			log.Logf(stdout.InferredType, name, typ)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,49 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReconcilePlaceholders(t *testing.T) {
+	existing := map[string]string{"kept": "a description", "old": "not used"}
+	found := map[string]string{"kept": "", "new": ""}
+
+	tests := []struct {
+		name  string
+		prune bool
+		want  map[string]string
+		wantC *placeholderChanges
+	}{
+		{
+			"keep-unused",
+			false,
+			map[string]string{"kept": "a description", "new": "", "old": "not used"},
+			&placeholderChanges{added: []string{"new"}, kept: []string{"kept"}, unused: []string{"old"}},
+		},
+		{
+			"prune",
+			true,
+			map[string]string{"kept": "a description", "new": ""},
+			&placeholderChanges{added: []string{"new"}, kept: []string{"kept"}, removed: []string{"old"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotC := reconcilePlaceholders(existing, found, tt.prune)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(gotC, tt.wantC) {
+				t.Errorf("got changes %+v, want %+v", gotC, tt.wantC)
+			}
+
+			if !gotC.outOfDate() {
+				t.Errorf("want the manifest to be out of date")
+			}
+		})
+	}
+}
@@ -1,18 +1,10 @@
 package manifest
 
+import "github.com/kohirens/tmplpress/internal/press"
+
 var defaultJson = `{
-    "$schema": "https://github.com/kohirens/tmplpress/blob/main/template.schema.json",
-    "version": "2.2.0",
-	"copyAsIs": [
-		"*.exe",
-		"*.gif",
-		"*.jpg",
-		"*.mp3",
-		"*.pdf",
-		"*.png",
-		"*.tiff",
-		"*.wmv"
-	],
+    "$schema": "` + press.SchemaUrl + `",
+    "version": "` + press.SchemaVersion + `",
 	"emptyDirFile": ".empty"
 }
 `
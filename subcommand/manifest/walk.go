@@ -0,0 +1,454 @@
+package manifest
+
+import (
+	"bytes"
+	"github.com/kohirens/tmplpress/internal/press"
+	"sort"
+	"strings"
+	"text/template"
+	txtParse "text/template/parse"
+)
+
+// fieldRef A placeholder referenced in a template, along with the node it was
+// found in, so it can be located in the file. The kind is the type suggested
+// by how it is used, if any, such as a bool when it is the condition of an if.
+type fieldRef struct {
+	kind string
+	name string
+	node txtParse.Node
+	tree *txtParse.Tree
+}
+
+// location The file, line, and column of the reference.
+func (r *fieldRef) location() string {
+	loc, _ := r.tree.ErrorContext(r.node)
+	return loc
+}
+
+// fieldWalker Collect placeholders from the parse tree of a template. Only
+// fields of the data passed to the template are placeholders, so the walker
+// keeps track of when "." is no longer that data, such as inside a range or
+// with block.
+type fieldWalker struct {
+	refs    []*fieldRef
+	tmpl    *template.Template
+	tree    *txtParse.Tree
+	notRoot map[string]bool
+	visited map[string]bool
+}
+
+// walkTemplate Find every placeholder referenced in a template, including the
+// templates it defines.
+func walkTemplate(t *template.Template) []*fieldRef {
+	w := &fieldWalker{
+		tmpl:    t,
+		notRoot: make(map[string]bool),
+		visited: make(map[string]bool),
+	}
+
+	w.walkTree(t.Name(), true)
+
+	// Templates that are defined but never called are still checked, assuming
+	// they will be passed the data. Those defined in partials are checked on
+	// their own.
+	for _, dt := range t.Templates() {
+		if dt.Tree == nil || t.Tree == nil || dt.Tree.ParseName != t.Tree.ParseName {
+			continue
+		}
+
+		if !w.notRoot[dt.Name()] {
+			w.walkTree(dt.Name(), true)
+		}
+	}
+
+	return w.refs
+}
+
+// walkPartials Find every placeholder referenced in the partials of a
+// template, each partial file is walked as a template of its own.
+func walkPartials(partials *template.Template) []*fieldRef {
+	if partials == nil {
+		return nil
+	}
+
+	var names []string
+	for _, t := range partials.Templates() {
+		if t.Tree != nil && t.Name() == t.Tree.ParseName {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var refs []*fieldRef
+	for _, name := range names {
+		refs = append(refs, walkTemplate(partials.Lookup(name))...)
+	}
+
+	return refs
+}
+
+// walkFile Find every placeholder referenced by a file of a template, in its
+// path, its front matter, and its content, unless the front matter says the
+// file is output raw. The pressed path is where the file is pressed from,
+// which differs for files in the substitute directory.
+func walkFile(relativePath, pressedPath string, content []byte, tm *press.TmplManifest, partials *template.Template) ([]*fieldRef, error) {
+	fm, body, e1 := press.ReadFrontMatter(content)
+	if e1 != nil {
+		return nil, e1
+	}
+
+	refs, e2 := walkText(relativePath, relativePath)
+	if e2 != nil {
+		return nil, e2
+	}
+
+	if fm == nil {
+		fm = &press.FrontMatter{}
+	}
+
+	for _, text := range []string{fm.If, fm.Path} {
+		fmRefs, e := walkText(relativePath, text)
+		if e != nil {
+			return nil, e
+		}
+		refs = append(refs, fmRefs...)
+	}
+
+	if !fm.Raw {
+		t, e3 := press.NewTemplate(relativePath, partials)
+		if e3 != nil {
+			return nil, e3
+		}
+
+		// Blank lines in place of the front matter keep the line numbers the
+		// same as in the file.
+		lines := strings.Repeat("\n", bytes.Count(content[:len(content)-len(body)], []byte("\n")))
+
+		if _, e := t.Delims(tm.FileDelims(pressedPath, fm)).Parse(lines + string(body)); e != nil {
+			return nil, e
+		}
+
+		refs = append(refs, walkTemplate(t)...)
+	}
+
+	if fe := tm.ForEachFor(pressedPath); fe != nil {
+		return walkForEach(fe, relativePath, refs)
+	}
+
+	return refs, nil
+}
+
+// walkForEach Find the placeholders of a file pressed for each item of a
+// list. The item is not a placeholder, but the list is.
+func walkForEach(fe *press.ForEach, relativePath string, refs []*fieldRef) ([]*fieldRef, error) {
+	pathRefs, e1 := walkText(relativePath, fe.Path)
+	if e1 != nil {
+		return nil, e1
+	}
+
+	listRefs, e2 := walkText(relativePath, "{{."+fe.List+"}}")
+	if e2 != nil {
+		return nil, e2
+	}
+
+	for _, ref := range listRefs {
+		ref.kind = press.TypeList
+	}
+
+	var res []*fieldRef
+	for _, ref := range append(append(listRefs, pathRefs...), refs...) {
+		if ref.name != fe.ItemName() {
+			res = append(res, ref)
+		}
+	}
+
+	return res, nil
+}
+
+// walkText Find the placeholders in a short template, such as the path of a
+// file.
+func walkText(name, text string) ([]*fieldRef, error) {
+	if !strings.Contains(text, "{{") {
+		return nil, nil
+	}
+
+	t, e := template.New(name).Funcs(press.FuncMap).Parse(text)
+	if e != nil {
+		return nil, e
+	}
+
+	return walkTemplate(t), nil
+}
+
+// walkTree Walk a named template once.
+func (w *fieldWalker) walkTree(name string, dotIsRoot bool) {
+	if w.visited[name] {
+		return
+	}
+
+	t := w.tmpl.Lookup(name)
+	if t == nil || t.Tree == nil || t.Tree.Root == nil {
+		return
+	}
+
+	w.visited[name] = true
+
+	prev := w.tree
+	w.tree = t.Tree
+	w.walk(t.Tree.Root, dotIsRoot)
+	w.tree = prev
+}
+
+func (w *fieldWalker) walk(node txtParse.Node, dotIsRoot bool) {
+	switch n := node.(type) {
+	case *txtParse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			w.walk(c, dotIsRoot)
+		}
+	case *txtParse.ActionNode:
+		w.walkPipe(n.Pipe, dotIsRoot)
+	case *txtParse.IfNode:
+		w.walkPipeAs(n.Pipe, dotIsRoot, press.TypeBool)
+		w.walk(n.List, dotIsRoot)
+		w.walk(n.ElseList, dotIsRoot)
+	case *txtParse.RangeNode:
+		// Inside the range "." is each element, but not in the else.
+		w.walkPipeAs(n.Pipe, dotIsRoot, press.TypeList)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, dotIsRoot)
+	case *txtParse.WithNode:
+		w.walkPipe(n.Pipe, dotIsRoot)
+		w.walk(n.List, false)
+		w.walk(n.ElseList, dotIsRoot)
+	case *txtParse.TemplateNode:
+		w.walkPipe(n.Pipe, dotIsRoot)
+		if dotIsRoot && isRootPipe(n.Pipe) {
+			w.walkTree(n.Name, true)
+		} else {
+			// It is given something other than the data, so its fields are
+			// not placeholders unless it is also called with the data.
+			w.notRoot[n.Name] = true
+		}
+	}
+}
+
+func (w *fieldWalker) walkPipe(pipe *txtParse.PipeNode, dotIsRoot bool) {
+	if pipe == nil {
+		return
+	}
+
+	for i, cmd := range pipe.Cmds {
+		kinds, _ := argKinds(cmd)
+
+		// The result of a command is passed as the last argument of the next,
+		// which may say what type it is, for example: .Port | printf "%d"
+		if i+1 < len(pipe.Cmds) && len(cmd.Args) == 1 {
+			_, piped := argKinds(pipe.Cmds[i+1])
+			kinds = []string{piped}
+		}
+
+		for j, arg := range cmd.Args {
+			kind := ""
+			if j < len(kinds) {
+				kind = kinds[j]
+			}
+			w.walkArg(arg, dotIsRoot, kind)
+		}
+	}
+}
+
+// walkPipeAs Walk a pipeline, and when it is only a field, use it as kind.
+func (w *fieldWalker) walkPipeAs(pipe *txtParse.PipeNode, dotIsRoot bool, kind string) {
+	if pipe != nil && len(pipe.Cmds) == 1 && len(pipe.Cmds[0].Args) == 1 {
+		w.walkArg(pipe.Cmds[0].Args[0], dotIsRoot, kind)
+		return
+	}
+
+	w.walkPipe(pipe, dotIsRoot)
+}
+
+func (w *fieldWalker) walkArg(arg txtParse.Node, dotIsRoot bool, kind string) {
+	switch n := arg.(type) {
+	case *txtParse.FieldNode:
+		// Only the first field of a chain, such as .A in .A.B, is a placeholder,
+		// and the kind is that of the last field.
+		if dotIsRoot {
+			w.add(n.Ident[0], n, kindOfChain(kind, len(n.Ident)))
+		}
+	case *txtParse.ChainNode:
+		if isRootNode(n.Node) {
+			if dotIsRoot && len(n.Field) > 0 {
+				w.add(n.Field[0], n, kindOfChain(kind, len(n.Field)))
+			}
+			return
+		}
+		w.walkArg(n.Node, dotIsRoot, "")
+	case *txtParse.VariableNode:
+		// "$" is always the data passed to the template.
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			w.add(n.Ident[1], n, kindOfChain(kind, len(n.Ident)-1))
+		}
+	case *txtParse.PipeNode:
+		w.walkPipeAs(n, dotIsRoot, kind)
+	}
+}
+
+func (w *fieldWalker) add(name string, node txtParse.Node, kind string) {
+	// The data files and metadata are given to the template next to the
+	// placeholders.
+	if press.IsReservedName(name) {
+		return
+	}
+
+	w.refs = append(w.refs, &fieldRef{kind: kind, name: name, node: node, tree: w.tree})
+}
+
+// argKinds The kinds suggested for each argument of a command by the function
+// it calls, and the kind of a value piped into it.
+func argKinds(cmd *txtParse.CommandNode) ([]string, string) {
+	ident, ok := cmd.Args[0].(*txtParse.IdentifierNode)
+	if !ok {
+		return nil, ""
+	}
+
+	kinds := make([]string, len(cmd.Args))
+
+	switch ident.Ident {
+	case "not":
+		for i := range kinds {
+			kinds[i] = press.TypeBool
+		}
+		return kinds, press.TypeBool
+	case "printf":
+		if len(cmd.Args) < 2 {
+			return nil, ""
+		}
+		format, isStr := cmd.Args[1].(*txtParse.StringNode)
+		if !isStr {
+			return nil, ""
+		}
+		verbs := printfVerbs(format.Text)
+		for i := 2; i < len(cmd.Args) && i-2 < len(verbs); i++ {
+			kinds[i] = verbKind(verbs[i-2])
+		}
+		piped := ""
+		if len(cmd.Args)-2 < len(verbs) {
+			piped = verbKind(verbs[len(cmd.Args)-2])
+		}
+		return kinds, piped
+	case "eq", "ne", "lt", "le", "gt", "ge":
+		// Comparing to a literal suggests the type of the other arguments.
+		kind := ""
+		for _, arg := range cmd.Args[1:] {
+			switch a := arg.(type) {
+			case *txtParse.NumberNode:
+				if a.IsInt {
+					kind = press.TypeInt
+				}
+			case *txtParse.BoolNode:
+				kind = press.TypeBool
+			}
+		}
+		for i := 1; i < len(kinds); i++ {
+			kinds[i] = kind
+		}
+		return kinds, kind
+	}
+
+	return nil, ""
+}
+
+// kindOfChain The kind applies to the last field of a chain, so it is only
+// the kind of a placeholder that is not chained.
+func kindOfChain(kind string, fields int) string {
+	if fields > 1 {
+		return ""
+	}
+
+	return kind
+}
+
+// printfVerbs The verbs of a printf format, in order, such as 'd' for "%5d".
+func printfVerbs(format string) []byte {
+	var verbs []byte
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		// Skip the flags, width, precision, and argument index.
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0 {
+			i++
+		}
+
+		if i < len(format) && format[i] != '%' {
+			verbs = append(verbs, format[i])
+		}
+	}
+
+	return verbs
+}
+
+// verbKind The kind of value a printf verb expects.
+func verbKind(verb byte) string {
+	switch verb {
+	case 'd':
+		return press.TypeInt
+	case 't':
+		return press.TypeBool
+	}
+
+	return ""
+}
+
+// inferTypes The type of each placeholder, for those used in a way that
+// suggests one. Placeholders used in ways that disagree are left out.
+func inferTypes(refs []*fieldRef) map[string]string {
+	types := make(map[string]string)
+	conflict := make(map[string]bool)
+
+	for _, ref := range refs {
+		if ref.kind == "" || conflict[ref.name] {
+			continue
+		}
+
+		if kind, ok := types[ref.name]; ok && kind != ref.kind {
+			delete(types, ref.name)
+			conflict[ref.name] = true
+			continue
+		}
+
+		types[ref.name] = ref.kind
+	}
+
+	return types
+}
+
+// isRootPipe Indicates a pipeline is only "." or "$", such as the data a
+// template is called with.
+func isRootPipe(pipe *txtParse.PipeNode) bool {
+	if pipe == nil || len(pipe.Decl) > 0 || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+
+	return isRootNode(pipe.Cmds[0].Args[0])
+}
+
+// isRootNode Indicates a node is ".", "$", or either in parentheses.
+func isRootNode(node txtParse.Node) bool {
+	switch n := node.(type) {
+	case *txtParse.DotNode:
+		return true
+	case *txtParse.VariableNode:
+		return len(n.Ident) == 1 && n.Ident[0] == "$"
+	case *txtParse.PipeNode:
+		return isRootPipe(n)
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package manifest
+
+import (
+	"github.com/kohirens/tmplpress/internal/press"
+	"reflect"
+	"sort"
+	"testing"
+	"text/template"
+)
+
+func TestWalkTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"action", `{{ .appName }}`, []string{"appName"}},
+		{"function-args", `{{ toUpper .a | printf "%v-%v" .b }}`, []string{"a", "b"}},
+		{"chained", `{{ .a.b.c }} {{ (.).d }}`, []string{"a", "d"}},
+		{"if-else", `{{ if .a }}{{ .b }}{{ else if .c }}{{ .d }}{{ else }}{{ .e }}{{ end }}`, []string{"a", "b", "c", "d", "e"}},
+		{"range", `{{ range $i, $v := .list }}{{ .notField }}{{ $.a }}{{ else }}{{ .b }}{{ end }}`, []string{"list", "a", "b"}},
+		{"with", `{{ with .a }}{{ .notField }}{{ $.b }}{{ else }}{{ .c }}{{ end }}`, []string{"a", "b", "c"}},
+		{"variables", `{{ $x := .a }}{{ $x.notField }}{{ $.b.c }}`, []string{"a", "b"}},
+		{"define", `{{ define "t1" }}{{ .a }}{{ end }}{{ template "t1" . }}{{ template "t1" $ }}`, []string{"a"}},
+		{"define-not-root", `{{ define "t1" }}{{ .notField }}{{ end }}{{ template "t1" .a }}`, []string{"a"}},
+		{"define-not-called", `{{ define "t1" }}{{ .a }}{{ end }}`, []string{"a"}},
+		{"no-fields", `{{/* .comment */}}{{ "text" }}{{ 1 }}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, e := template.New(tt.name).Funcs(press.FuncMap).Parse(tt.text)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			var got []string
+			for _, ref := range walkTemplate(tmpl) {
+				got = append(got, ref.name)
+			}
+
+			sort.Strings(got)
+			sort.Strings(tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldRefLocation(t *testing.T) {
+	tmpl, _ := template.New("file.txt").Parse("line 1\n{{ .a }}")
+
+	refs := walkTemplate(tmpl)
+	if len(refs) != 1 {
+		t.Fatalf("got %v references, want 1", len(refs))
+	}
+
+	if got := refs[0].location(); got != "file.txt:2:3" {
+		t.Errorf("got %q, want %q", got, "file.txt:2:3")
+	}
+}
+
+func TestInferTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want map[string]string
+	}{
+		{"if", `{{ if .useDocker }}{{ end }}`, map[string]string{"useDocker": "bool"}},
+		{"if-not", `{{ if not .useDocker }}{{ end }}`, map[string]string{"useDocker": "bool"}},
+		{"range", `{{ range .ports }}{{ . }}{{ end }}`, map[string]string{"ports": "list"}},
+		{"printf-piped", `{{ .port | printf "%d" }}`, map[string]string{"port": "int"}},
+		{"printf-args", `{{ printf "%v:%05d %t" .host .port .debug }}`, map[string]string{"port": "int", "debug": "bool"}},
+		{"printf-escaped", `{{ printf "%%d %s" .name }}`, map[string]string{}},
+		{"compare", `{{ if gt .replicas 1 }}{{ end }}{{ if eq .debug true }}{{ end }}`, map[string]string{"replicas": "int", "debug": "bool"}},
+		{"chained", `{{ if .app.debug }}{{ end }}`, map[string]string{}},
+		{"conflict", `{{ if .port }}{{ end }}{{ printf "%d" .port }}`, map[string]string{}},
+		{"no-type", `{{ .name }}{{ if and .a .b }}{{ end }}`, map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, e := template.New(tt.name).Funcs(press.FuncMap).Parse(tt.text)
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			got := inferTypes(walkTemplate(tmpl))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package templatize
+
+var stderr = struct {
+	DecodeMapping      string
+	InvalidPair        string
+	InvalidPlaceholder string
+	NoMapping          string
+	OutNotEmpty        string
+	ParseInput         string
+	SaveManifest       string
+}{
+	DecodeMapping:      "could not decode the mapping in %v, it should be an object of literal values to placeholder names: %v",
+	InvalidPair:        "%q is not a literal=placeholder pair",
+	InvalidPlaceholder: "placeholder name %q for %q must start with a letter, followed by letters, numbers, or underscores",
+	NoMapping:          "no literal values to replace, use -map or -set",
+	OutNotEmpty:        "the template directory %v already exists and is not empty",
+	ParseInput:         "could not parse templatize input: %v",
+	SaveManifest:       "could not save file %v, error: %v",
+}
+
+var stdout = struct {
+	CopyAsIs        string
+	PlaceholderDesc string
+	Templatizing    string
+}{
+	CopyAsIs:        "binary file %v will be copied as-is",
+	PlaceholderDesc: "replaces %q",
+	Templatizing:    "templatizing %v",
+}
+
+var UsageMessages = map[string]string{
+	"help":    "Display this usage information.",
+	"MapFile": "JSON file of literal values mapped to placeholder names.",
+	"Values":  "a literal=placeholder pair, can be repeated.",
+}
+
+// UsageTmpl Usage information template of this command.
+const UsageTmpl = `
+Usage: {{.AppName}} {{.Command}} [-map <file>] [-set <literal=placeholder>]... <project> <template>
+
+Copy a project to a new template directory, replacing each literal value with
+its placeholder in the content and the paths of the files. Delimiters already
+in a file are escaped, so they are output as they are. Binary files are copied
+without changes, and added to "copyAsIs". A template.json is then generated for
+the template.
+
+The mapping file is a JSON object of literal values to placeholder names:
+
+	{
+		"github.com/acme/billing": "modulePath",
+		"billing": "serviceName",
+		"8080": "port"
+	}
+
+examples:
+
+	$ {{.AppName}} {{.Command}} -map ./mapping.json ./billing ./service-template
+
+	$ {{.AppName}} {{.Command}} -set billing=serviceName -set 8080=port ./billing ./service-template
+
+`
+
+var UsageVars = map[string]string{}
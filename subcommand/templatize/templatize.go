@@ -0,0 +1,306 @@
+// Package templatize turns an existing project into a template, the reverse of
+// pressing one.
+package templatize
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/kohirens/stdlib/fsio"
+	"github.com/kohirens/stdlib/log"
+	"github.com/kohirens/tmplpress/internal/msg"
+	"github.com/kohirens/tmplpress/internal/press"
+	"github.com/kohirens/tmplpress/subcommand/manifest"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	Name    = "templatize"
+	Summary = "Turn an existing project into a template."
+	dirMode = 0744
+	// fileMode The mode of the files made for the template, as opposed to
+	// those copied from the project.
+	fileMode = 0644
+	// emptyDirFile Marks the empty directories of the project in the template.
+	emptyDirFile = ".empty"
+	gitConfigDir = ".git"
+	ps           = string(os.PathSeparator)
+)
+
+type Arguments struct {
+	MapFile string  // JSON file mapping literal values to placeholder names.
+	Values  mapping // literal values mapped to placeholder names with -set.
+}
+
+// mapping Literal values in a project mapped to placeholder names.
+type mapping map[string]string
+
+func (m mapping) String() string {
+	pairs := make([]string, 0, len(m))
+	for literal, name := range m {
+		pairs = append(pairs, literal+"="+name)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// Set Add a literal=name pair, the literal may contain "=".
+func (m mapping) Set(value string) error {
+	i := strings.LastIndex(value, "=")
+	if i < 1 {
+		return fmt.Errorf(stderr.InvalidPair, value)
+	}
+
+	m[value[:i]] = value[i+1:]
+
+	return nil
+}
+
+var (
+	input = Arguments{Values: mapping{}}
+	flags *flag.FlagSet
+	help  bool
+	// rePlaceholder Placeholders have to be names a template can use as a field.
+	rePlaceholder = regexp.MustCompile(`^\p{L}[\p{L}\p{N}_]*$`)
+)
+
+func Init() *flag.FlagSet {
+	flags = flag.NewFlagSet(Name, flag.ExitOnError)
+
+	flags.BoolVar(&help, "help", false, UsageMessages["help"])
+	flags.StringVar(&input.MapFile, "map", "", UsageMessages["MapFile"])
+	input.Values = mapping{}
+	flags.Var(input.Values, "set", UsageMessages["Values"])
+
+	return flags
+}
+
+// Run Make a template from a project.
+func Run(ca []string) error {
+	if e := flags.Parse(ca); e != nil {
+		return fmt.Errorf(stderr.ParseInput, e.Error())
+	}
+
+	if help {
+		flags.Usage()
+		return nil
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf(msg.Stderr.InvalidNoSubCmdArgs, Name, 2)
+	}
+
+	values, e1 := loadMapping(input.MapFile, input.Values)
+	if e1 != nil {
+		return e1
+	}
+
+	filename, e2 := Templatize(flags.Arg(0), flags.Arg(1), values)
+	if e2 != nil {
+		return e2
+	}
+
+	log.Logf(msg.Stdout.GeneratedManifest, filename)
+
+	return nil
+}
+
+// Templatize Copy a project to a new template, replacing the literal values
+// with placeholders in the content and the paths of its files. Existing
+// delimiters are escaped so they are output as they are. Binary files are
+// copied without changes, and added to copyAsIs. Returns the path of the
+// generated template manifest.
+func Templatize(project, out string, values map[string]string) (string, error) {
+	if len(values) == 0 {
+		return "", fmt.Errorf(stderr.NoMapping)
+	}
+
+	for literal, name := range values {
+		if !rePlaceholder.MatchString(name) {
+			return "", fmt.Errorf(stderr.InvalidPlaceholder, name, literal)
+		}
+	}
+
+	if !fsio.Exist(project) {
+		return "", fmt.Errorf(msg.Stderr.PathNotExist, project)
+	}
+
+	if entries, _ := os.ReadDir(out); len(entries) > 0 {
+		return "", fmt.Errorf(stderr.OutNotEmpty, out)
+	}
+
+	srcDir, e1 := filepath.Abs(project)
+	if e1 != nil {
+		return "", fmt.Errorf(msg.Stderr.NoPath, e1.Error())
+	}
+
+	outDir, e2 := filepath.Abs(out)
+	if e2 != nil {
+		return "", fmt.Errorf(msg.Stderr.NoPath, e2.Error())
+	}
+
+	t := &templatizer{
+		srcDir:   srcDir,
+		outDir:   outDir,
+		replacer: newReplacer(values),
+	}
+
+	if e := filepath.WalkDir(srcDir, t.templatizeFile); e != nil {
+		return "", e
+	}
+
+	// Start the manifest with what only templatize knows, then let generate
+	// fill in the rest, the same as for any template.
+	tm := &press.TmplManifest{
+		CopyAsIs:     t.binaries,
+		EmptyDirFile: emptyDirFile,
+		Placeholders: make(map[string]string),
+		Version:      press.SchemaVersion,
+	}
+
+	for _, literal := range sortedLiterals(values) {
+		name := values[literal]
+		if _, ok := tm.Placeholders[name]; !ok {
+			tm.Placeholders[name] = fmt.Sprintf(stdout.PlaceholderDesc, literal)
+		}
+	}
+
+	filename := outDir + ps + press.TmplManifestFile
+
+	data, e3 := json.MarshalIndent(tm, "", "    ")
+	if e3 != nil {
+		return "", fmt.Errorf(stderr.SaveManifest, filename, e3.Error())
+	}
+
+	if e := os.WriteFile(filename, data, fileMode); e != nil {
+		return "", fmt.Errorf(stderr.SaveManifest, filename, e.Error())
+	}
+
+	return manifest.GenerateATemplateManifest(outDir, "", false, false)
+}
+
+// templatizer Copy the files of a project into a template.
+type templatizer struct {
+	binaries []string
+	outDir   string
+	replacer *strings.Replacer
+	srcDir   string
+}
+
+func (t *templatizer) templatizeFile(sourcePath string, d fs.DirEntry, wErr error) error {
+	if wErr != nil {
+		return wErr
+	}
+
+	// Do not copy the template into itself, when it is made in the project.
+	if sourcePath == t.outDir || (d.IsDir() && d.Name() == gitConfigDir) {
+		return filepath.SkipDir
+	}
+
+	relativePath := strings.TrimLeft(strings.TrimPrefix(sourcePath, t.srcDir), "\\/")
+	if relativePath == "" || relativePath == press.TmplManifestFile {
+		return nil
+	}
+
+	outPath := t.replacer.Replace(relativePath)
+	dstPath := t.outDir + ps + outPath
+
+	if d.IsDir() {
+		if e := os.MkdirAll(dstPath, dirMode); e != nil {
+			return e
+		}
+
+		// Keep empty directories, since Git will not.
+		if entries, _ := os.ReadDir(sourcePath); len(entries) == 0 {
+			return os.WriteFile(dstPath+ps+emptyDirFile, nil, fileMode)
+		}
+
+		return nil
+	}
+
+	info, e1 := d.Info()
+	if e1 != nil {
+		return e1
+	}
+
+	content, e2 := os.ReadFile(sourcePath)
+	if e2 != nil {
+		return fmt.Errorf(msg.Stderr.CannotReadFile, sourcePath, e2.Error())
+	}
+
+	if press.IsBinary(content) {
+		log.Infof(stdout.CopyAsIs, relativePath)
+		t.binaries = append(t.binaries, press.LiteralPattern(outPath))
+	} else {
+		log.Infof(stdout.Templatizing, relativePath)
+		content = []byte(t.replacer.Replace(string(content)))
+	}
+
+	if e := os.MkdirAll(filepath.Dir(dstPath), dirMode); e != nil {
+		return e
+	}
+
+	return os.WriteFile(dstPath, content, info.Mode().Perm())
+}
+
+// loadMapping Combine the mapping from a JSON file with those given by -set,
+// which take precedence.
+func loadMapping(filename string, values mapping) (map[string]string, error) {
+	res := make(map[string]string)
+
+	if filename != "" {
+		content, e1 := os.ReadFile(filename)
+		if e1 != nil {
+			return nil, fmt.Errorf(msg.Stderr.CannotReadFile, filename, e1.Error())
+		}
+
+		if e := json.Unmarshal(content, &res); e != nil {
+			return nil, fmt.Errorf(stderr.DecodeMapping, filename, e.Error())
+		}
+	}
+
+	for literal, name := range values {
+		res[literal] = name
+	}
+
+	return res, nil
+}
+
+// newReplacer Replace each literal with an action of its placeholder, and
+// escape delimiters already in the file. Longer literals are replaced first,
+// so a module path wins over the service name it contains.
+func newReplacer(values map[string]string) *strings.Replacer {
+	var pairs []string
+
+	for _, literal := range sortedLiterals(values) {
+		pairs = append(pairs, literal, "{{."+values[literal]+"}}")
+	}
+
+	pairs = append(pairs, "{{", `{{"{{"}}`, "}}", `{{"}}"}}`)
+
+	return strings.NewReplacer(pairs...)
+}
+
+// sortedLiterals The literals longest first, then in alphabetical order.
+func sortedLiterals(values map[string]string) []string {
+	literals := make([]string, 0, len(values))
+	for literal := range values {
+		literals = append(literals, literal)
+	}
+
+	sort.Slice(literals, func(i, j int) bool {
+		if len(literals[i]) != len(literals[j]) {
+			return len(literals[i]) > len(literals[j])
+		}
+		return literals[i] < literals[j]
+	})
+
+	return literals
+}
@@ -0,0 +1,141 @@
+package templatize
+
+import (
+	"github.com/kohirens/stdlib/fsio"
+	"github.com/kohirens/tmplpress/internal/press"
+	"github.com/kohirens/tmplpress/internal/test"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	fixtureDir = "testdata"
+	tmpDir     = "tmp"
+)
+
+func TestMain(m *testing.M) {
+	press.UseSchemas(test.Schemas())
+
+	_ = os.RemoveAll(tmpDir)
+
+	os.Exit(m.Run())
+}
+
+// TestTemplatizeRoundTrip Pressing the template made from a project, with the
+// literal values as answers, gives back the project.
+func TestTemplatizeRoundTrip(t *testing.T) {
+	project := fixtureDir + ps + "project-01"
+	out := tmpDir + ps + "project-01-template"
+	pressed := tmpDir + ps + "project-01-pressed"
+	values := map[string]string{
+		"github.com/acme/billing": "modulePath",
+		"billing":                 "serviceName",
+		"8080":                    "port",
+	}
+
+	filename, err := Templatize(project, out, values)
+	if err != nil {
+		t.Fatalf("Templatize() error = %v", err)
+	}
+
+	tm, e1 := press.ReadTemplateJson(filename)
+	if e1 != nil {
+		t.Fatal(e1)
+	}
+
+	wantPlaceholders := map[string]string{
+		"modulePath":  `replaces "github.com/acme/billing"`,
+		"port":        `replaces "8080"`,
+		"serviceName": `replaces "billing"`,
+	}
+	if !reflect.DeepEqual(tm.Placeholders, wantPlaceholders) {
+		t.Errorf("got placeholders %v, want %v", tm.Placeholders, wantPlaceholders)
+	}
+
+	if info, e := os.Stat(filename); e == nil && info.Mode().Perm()&0111 != 0 {
+		t.Errorf("the manifest should not be executable, got %v", info.Mode())
+	}
+
+	if !reflect.DeepEqual(tm.CopyAsIs, []string{"/logo.bin"}) {
+		t.Errorf("got copyAsIs %v, want [/logo.bin]", tm.CopyAsIs)
+	}
+
+	if !fsio.Exist(out + ps + "cmd" + ps + "{{.serviceName}}" + ps + "main.go") {
+		t.Errorf("the path of cmd/billing/main.go was not templatized")
+	}
+
+	answers := map[string]string{
+		"modulePath":  "github.com/acme/billing",
+		"port":        "8080",
+		"serviceName": "billing",
+	}
+	if e := press.Print(out, pressed, answers, tm, nil); e != nil {
+		t.Fatalf("Print() error = %v", e)
+	}
+
+	for _, file := range []string{"go.mod", "logo.bin", "cmd/billing/main.go"} {
+		want, _ := os.ReadFile(project + ps + filepath.FromSlash(file))
+		got, e := os.ReadFile(pressed + ps + filepath.FromSlash(file))
+		if e != nil {
+			t.Errorf("%v was not pressed: %v", file, e)
+			continue
+		}
+
+		if string(got) != string(want) {
+			t.Errorf("pressed %v = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestTemplatizeErrors(t *testing.T) {
+	_ = os.MkdirAll(tmpDir+ps+"not-empty", dirMode)
+	_ = os.WriteFile(tmpDir+ps+"not-empty"+ps+"file.txt", nil, fileMode)
+
+	tests := []struct {
+		name   string
+		out    string
+		values map[string]string
+	}{
+		{"no-mapping", tmpDir + ps + "no-mapping", nil},
+		{"invalid-name", tmpDir + ps + "invalid-name", map[string]string{"billing": "service-name"}},
+		{"out-not-empty", tmpDir + ps + "not-empty", map[string]string{"billing": "serviceName"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Templatize(fixtureDir+ps+"project-01", tt.out, tt.values); err == nil {
+				t.Errorf("Templatize() want an error")
+			}
+		})
+	}
+}
+
+func TestMappingSet(t *testing.T) {
+	m := mapping{}
+
+	if e := m.Set("a=b=name"); e != nil {
+		t.Fatal(e)
+	}
+
+	if m["a=b"] != "name" {
+		t.Errorf("got %v, want a=b mapped to name", m)
+	}
+
+	if e := m.Set("=name"); e == nil {
+		t.Errorf("want an error for an empty literal")
+	}
+}
+
+func TestLoadMapping(t *testing.T) {
+	got, err := loadMapping(fixtureDir+ps+"mapping-01.json", mapping{"8080": "httpPort"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"billing": "serviceName", "8080": "httpPort"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
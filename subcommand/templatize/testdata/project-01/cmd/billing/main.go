@@ -0,0 +1,6 @@
+package main
+
+import "text/template"
+
+// billing listens on port 8080.
+var t = template.Must(template.New("billing").Parse("{{ .Name }}"))